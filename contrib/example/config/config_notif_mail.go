@@ -8,7 +8,7 @@ type NotifMail struct {
 	InsecureSkipVerify *bool  `yaml:"insecureSkipVerify,omitempty" json:"insecureSkipVerify,omitempty"`
 	Username           string `yaml:"username,omitempty" json:"username,omitempty"`
 	UsernameFile       string `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty"`
-	Password           string `yaml:"password,omitempty" json:"password,omitempty"`
+	Password           string `yaml:"password,omitempty" json:"password,omitempty" secret:"true"`
 	PasswordFile       string `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty"`
 	From               string `yaml:"from,omitempty" json:"from,omitempty"`
 	To                 string `yaml:"to,omitempty" json:"to,omitempty"`
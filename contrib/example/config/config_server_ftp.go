@@ -10,7 +10,7 @@ type ServerFTP struct {
 	Port               int            `yaml:"port,omitempty" json:"port,omitempty" validate:"required,min=1"`
 	Username           string         `yaml:"username,omitempty" json:"username,omitempty"`
 	UsernameFile       string         `yaml:"usernameFile,omitempty" json:"usernameFile,omitempty" validate:"omitempty,file"`
-	Password           string         `yaml:"password,omitempty" json:"password,omitempty"`
+	Password           string         `yaml:"password,omitempty" json:"password,omitempty" secret:"true"`
 	PasswordFile       string         `yaml:"passwordFile,omitempty" json:"passwordFile,omitempty" validate:"omitempty,file"`
 	Sources            []string       `yaml:"sources,omitempty" json:"sources,omitempty"`
 	Timeout            *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
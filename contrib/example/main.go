@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"log"
 	"os"
 
@@ -23,7 +22,6 @@ func main() {
 		Db: (&config.Db{}).GetDefaults(),
 	}
 
-	// Load from file(s)
 	fileLoader := gonfig.NewFileLoader(gonfig.FileLoaderConfig{
 		Filename: "/path/to/myapp.yml",
 		Finder: gonfig.Finder{
@@ -31,42 +29,42 @@ func main() {
 			Extensions: []string{"yaml", "yml"},
 		},
 	})
-	if found, err := fileLoader.Load(&cfg); err != nil {
-		log.Fatal(errors.Wrap(err, "Failed to decode configuration from file"))
-	} else if !found {
-		log.Println("No configuration file found")
-	} else {
-		log.Printf("Configuration loaded from file: %s", fileLoader.GetFilename())
-	}
-
-	// Load from flags
 	flagsLoader := gonfig.NewFlagLoader(gonfig.FlagLoaderConfig{
 		Args: []string{
 			"--timezone=Europe/Paris",
 			"--logLevel=debug",
 		},
 	})
-	if found, err := flagsLoader.Load(&cfg); err != nil {
-		log.Fatal(errors.Wrap(err, "Failed to decode configuration from flags"))
-	} else if !found {
-		log.Println("No flags found")
-	} else {
-		log.Printf("Configuration loaded from flags")
-	}
-
-	// Load from environment variables
 	envLoader := gonfig.NewEnvLoader(gonfig.EnvLoaderConfig{
 		Prefix: "MYAPP_",
 	})
-	if found, err := envLoader.Load(&cfg); err != nil {
-		log.Fatal(errors.Wrap(err, "Failed to decode configuration from environment variables"))
-	} else if !found {
-		log.Println("No MYAPP_* environment variables defined")
-	} else {
-		log.Printf("Configuration loaded from %d environment variables\n", len(envLoader.GetVars()))
+
+	// Load from file(s), flags and environment variables, the latter overriding the flags
+	chain := gonfig.NewChain(gonfig.ChainConfig{
+		Loaders:    []gonfig.Loader{fileLoader, flagsLoader, envLoader},
+		Precedence: []gonfig.Source{gonfig.SourceFile, gonfig.SourceFlag, gonfig.SourceEnv},
+		OnLoad: func(res gonfig.LoaderResult) {
+			switch {
+			case !res.Found:
+				log.Printf("No configuration found from %s", res.Source)
+			case res.Source == gonfig.SourceFile:
+				log.Printf("Configuration loaded from file: %s", fileLoader.GetFilename())
+			case res.Source == gonfig.SourceEnv:
+				log.Printf("Configuration loaded from %d environment variables", len(envLoader.GetVars()))
+			default:
+				log.Printf("Configuration loaded from %s", res.Source)
+			}
+		},
+	})
+	if _, err := chain.Load(&cfg); err != nil {
+		// the usage has already been written on -h or --help
+		if errors.Is(err, gonfig.ErrHelp) {
+			os.Exit(0)
+		}
+		log.Fatal(errors.Wrap(err, "Failed to load configuration"))
 	}
 
-	// Display configuration
-	b, _ := json.MarshalIndent(cfg, "", "  ")
+	// Display configuration with secrets masked
+	b, _ := gonfig.Dump(&cfg, "json")
 	log.Print(string(b))
 }
@@ -0,0 +1,320 @@
+// Package docs generates the reference documentation of a typed Configuration,
+// listing each option with its file key, environment variable and flag.
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/gonfig/env"
+	"github.com/crazy-max/gonfig/file"
+	"github.com/crazy-max/gonfig/flag"
+	"github.com/crazy-max/gonfig/generator"
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// Opts holds options used when generating the documentation.
+type Opts struct {
+	// EnvPrefix is the prefix of the environment variables. Default to "GONFIG_".
+	EnvPrefix string
+}
+
+// Option is the reference of a configuration option.
+// Key, Env and Flag are empty if the option can't be set from the matching source.
+type Option struct {
+	// Key is the path of the option in a file, e.g. server.ftp.host.
+	Key string
+	// Env is the name of the environment variable, e.g. GONFIG_SERVER_FTP_HOST.
+	Env string
+	// Flag is the name of the flag, e.g. --server.ftp.host.
+	Flag string
+	// Type of the value: string, int, uint, float, bool, duration, list, map or section.
+	Type string
+	// Default value.
+	Default string
+	// Description from the description tag.
+	Description string
+}
+
+// Options returns the options of the type of the given element, which must be a pointer to a struct.
+// The defaults are the ones set by generator.Generate, the map keys are the parser.MapNamePlaceholder placeholder,
+// and the items of the slices of structs are documented as their first item.
+func Options(element interface{}, opts Opts) ([]Option, error) {
+	if opts.EnvPrefix == "" {
+		opts.EnvPrefix = env.DefaultNamePrefix
+	}
+
+	rType := reflect.TypeOf(element)
+	if rType == nil || rType.Kind() != reflect.Pointer || rType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported element type: %T", element)
+	}
+
+	sample := reflect.New(rType.Elem())
+	generator.Generate(sample.Interface())
+
+	flags, err := flag.Encode(sample.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	envVars, err := env.Encode(opts.EnvPrefix, sample.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	b := builder{
+		flags: make(map[string]string, len(flags)),
+		envs:  make(map[string]string, len(envVars)),
+	}
+	aliases, err := flag.Aliases(sample.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range flags {
+		b.flags[f.Name] = "--" + f.Name
+		if alias, ok := aliases[f.Name]; ok {
+			b.flags[f.Name] = alias.String()
+		}
+	}
+	for _, e := range envVars {
+		name := strings.ReplaceAll(strings.TrimPrefix(e.Name, opts.EnvPrefix), "_", ".")
+		b.envs[strings.ToLower(name)] = e.Name
+	}
+
+	b.walk(sample.Elem(), optionPath{file: true}, "")
+
+	return b.options, nil
+}
+
+// Markdown generates the reference documentation of the type of the given element as a Markdown table.
+func Markdown(element interface{}, opts Opts) ([]byte, error) {
+	options, err := Options(element, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("| Key | Environment variable | Flag | Type | Default | Description |\n")
+	buf.WriteString("|-----|----------------------|------|------|---------|-------------|\n")
+
+	for _, o := range options {
+		_, _ = fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(o.Key), markdownCode(o.Env), markdownCode(o.Flag), o.Type, markdownCode(o.Default),
+			markdownText(o.Description))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// HTML generates the reference documentation of the type of the given element as an HTML table.
+func HTML(element interface{}, opts Opts) ([]byte, error) {
+	options, err := Options(element, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("<table>\n  <thead>\n    <tr>\n")
+	for _, header := range []string{"Key", "Environment variable", "Flag", "Type", "Default", "Description"} {
+		_, _ = fmt.Fprintf(&buf, "      <th>%s</th>\n", header)
+	}
+	buf.WriteString("    </tr>\n  </thead>\n  <tbody>\n")
+
+	for _, o := range options {
+		buf.WriteString("    <tr>\n")
+		for _, value := range []string{htmlCode(o.Key), htmlCode(o.Env), htmlCode(o.Flag), html.EscapeString(o.Type), htmlCode(o.Default)} {
+			_, _ = fmt.Fprintf(&buf, "      <td>%s</td>\n", value)
+		}
+		_, _ = fmt.Fprintf(&buf, "      <td>%s</td>\n", strings.ReplaceAll(html.EscapeString(o.Description), "\n", "<br>"))
+		buf.WriteString("    </tr>\n")
+	}
+
+	buf.WriteString("  </tbody>\n</table>\n")
+
+	return buf.Bytes(), nil
+}
+
+type builder struct {
+	flags   map[string]string
+	envs    map[string]string
+	options []Option
+}
+
+// optionPath is the path of an option, as a file key and as a lower case name matching the flags and the env vars.
+type optionPath struct {
+	key  string
+	name string
+	// file reports whether the option can be set from a file.
+	file bool
+}
+
+func (p optionPath) child(field reflect.StructField) optionPath {
+	return optionPath{
+		key:  joinKey(p.key, file.KeyName(field.Name)),
+		name: joinKey(p.name, strings.ToLower(field.Name)),
+		file: p.file && field.Tag.Get(parser.TagFile) != "-",
+	}
+}
+
+func (p optionPath) entry(name string) optionPath {
+	return optionPath{key: joinKey(p.key, name), name: joinKey(p.name, strings.ToLower(name)), file: p.file}
+}
+
+func (p optionPath) item() optionPath {
+	return optionPath{key: p.key + "[0]", name: p.name + "[0]", file: p.file}
+}
+
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// walk adds the options of the value rValue found at path.
+func (b *builder) walk(rValue reflect.Value, path optionPath, description string) {
+	switch rValue.Kind() {
+	case reflect.Pointer:
+		if rValue.IsNil() {
+			return
+		}
+		b.walk(rValue.Elem(), path, description)
+
+	case reflect.Struct:
+		if rValue.Type() == reflect.TypeOf(time.Time{}) {
+			b.add(path, "string", formatValue(rValue), description)
+			return
+		}
+
+		for i := 0; i < rValue.NumField(); i++ {
+			field := rValue.Type().Field(i)
+
+			if !parser.IsExported(field) || field.Tag.Get(parser.TagDescription) == "-" {
+				continue
+			}
+
+			if field.Anonymous {
+				b.walk(rValue.Field(i), path, description)
+				continue
+			}
+
+			child := path.child(field)
+			fieldDescription := field.Tag.Get(parser.TagDescription)
+
+			if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct &&
+				(field.Tag.Get(parser.TagFile) == parser.TagLabelAllowEmpty || field.Tag.Get(parser.TagLabel) == parser.TagLabelAllowEmpty) {
+				b.add(child, "section", "", fieldDescription)
+			}
+
+			b.walk(rValue.Field(i), child, fieldDescription)
+		}
+
+	case reflect.Map:
+		keys := rValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			entry := path.entry(key.String())
+			value := rValue.MapIndex(key)
+
+			for value.Kind() == reflect.Interface && !value.IsNil() {
+				value = value.Elem()
+			}
+
+			switch value.Kind() {
+			case reflect.Struct, reflect.Pointer, reflect.Map:
+				b.walk(value, entry, description)
+			default:
+				b.add(entry, "map", formatValue(value), description)
+			}
+		}
+
+	case reflect.Slice:
+		elem := rValue.Type().Elem()
+		if elem.Kind() == reflect.Struct || elem.Kind() == reflect.Pointer && elem.Elem().Kind() == reflect.Struct {
+			if rValue.Len() > 0 {
+				b.walk(rValue.Index(0), path.item(), description)
+			}
+			return
+		}
+
+		b.add(path, "list", formatValue(rValue), description)
+
+	case reflect.Invalid, reflect.Interface:
+		b.add(path, "", "", description)
+
+	default:
+		b.add(path, typeName(rValue.Type()), formatValue(rValue), description)
+	}
+}
+
+func (b *builder) add(path optionPath, typ, value, description string) {
+	o := Option{
+		Env:         b.envs[path.name],
+		Flag:        b.flags[path.name],
+		Type:        typ,
+		Default:     value,
+		Description: description,
+	}
+
+	if path.file {
+		o.Key = path.key
+	}
+
+	if o.Key == "" && o.Env == "" && o.Flag == "" {
+		return
+	}
+
+	b.options = append(b.options, o)
+}
+
+func typeName(rType reflect.Type) string {
+	if hint := flag.TypeHint(rType); hint != "" {
+		return hint
+	}
+	return "string"
+}
+
+// formatValue formats a default value as written in files, an empty string for a zero value.
+func formatValue(rValue reflect.Value) string {
+	if !rValue.IsValid() || rValue.IsZero() {
+		return ""
+	}
+
+	switch value := file.EncodeScalar(rValue).(type) {
+	case nil:
+		return fmt.Sprint(rValue.Interface())
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, item := range value {
+			values[i] = fmt.Sprint(item)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(s, "|", `\|`) + "`"
+}
+
+func markdownText(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(s)
+}
+
+func htmlCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(s) + "</code>"
+}
@@ -0,0 +1,140 @@
+package docs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type server struct {
+	Host string `description:"Host of the server."`
+	Port int    `description:"Port of the server."`
+}
+
+func (s *server) SetDefaults() {
+	s.Port = 8080
+}
+
+type tracing struct {
+	Ratio float64 `description:"Sampling ratio."`
+}
+
+type config struct {
+	Name     string             `description:"Name of the application."`
+	Timeout  types.Duration     `description:"Timeout of the requests."`
+	Sources  []string           `description:"List of sources."`
+	Token    string             `file:"-" description:"Token, not allowed in files."`
+	Internal string             `label:"-" description:"Only in files."`
+	Hidden   string             `description:"-"`
+	Tracing  *tracing           `label:"allowEmpty" file:"allowEmpty" description:"Enable the tracing."`
+	Mirrors  []server           `description:"Mirrors."`
+	Notif    map[string]*server `description:"Notifiers by name."`
+	Labels   map[string]string  `description:"Labels | tags."`
+}
+
+func (c *config) SetDefaults() {
+	c.Name = "myapp"
+	c.Timeout = types.Duration(10 * time.Second)
+	c.Sources = []string{"/", "/tmp"}
+}
+
+func TestOptions(t *testing.T) {
+	options, err := Options(&config{}, Opts{EnvPrefix: "MYAPP_"})
+	require.NoError(t, err)
+
+	expected := []Option{
+		{Key: "name", Env: "MYAPP_NAME", Flag: "--name", Type: "string", Default: "myapp", Description: "Name of the application."},
+		{Key: "timeout", Env: "MYAPP_TIMEOUT", Flag: "--timeout", Type: "duration", Default: "10s", Description: "Timeout of the requests."},
+		{Key: "sources", Env: "MYAPP_SOURCES", Flag: "--sources", Type: "list", Default: "/,/tmp", Description: "List of sources."},
+		{Env: "MYAPP_TOKEN", Flag: "--token", Type: "string", Description: "Token, not allowed in files."},
+		{Key: "internal", Type: "string", Description: "Only in files."},
+		{Key: "tracing", Env: "MYAPP_TRACING", Flag: "--tracing", Type: "section", Description: "Enable the tracing."},
+		{Key: "tracing.ratio", Env: "MYAPP_TRACING_RATIO", Flag: "--tracing.ratio", Type: "float", Description: "Sampling ratio."},
+		{Key: "mirrors[0].host", Env: "MYAPP_MIRRORS[0]_HOST", Flag: "--mirrors[0].host", Type: "string", Description: "Host of the server."},
+		{Key: "mirrors[0].port", Env: "MYAPP_MIRRORS[0]_PORT", Flag: "--mirrors[0].port", Type: "int", Default: "8080", Description: "Port of the server."},
+		{Key: "notif.<name>.host", Env: "MYAPP_NOTIF_<NAME>_HOST", Flag: "--notif.<name>.host", Type: "string", Description: "Host of the server."},
+		{Key: "notif.<name>.port", Env: "MYAPP_NOTIF_<NAME>_PORT", Flag: "--notif.<name>.port", Type: "int", Default: "8080", Description: "Port of the server."},
+		{Key: "labels.<name>", Env: "MYAPP_LABELS_<NAME>", Flag: "--labels.<name>", Type: "map", Description: "Labels | tags."},
+	}
+	assert.Equal(t, expected, options)
+}
+
+func TestMarkdown(t *testing.T) {
+	content, err := Markdown(&server{}, Opts{})
+	require.NoError(t, err)
+
+	expected := "| Key | Environment variable | Flag | Type | Default | Description |\n" +
+		"|-----|----------------------|------|------|---------|-------------|\n" +
+		"| `host` | `GONFIG_HOST` | `--host` | string |  | Host of the server. |\n" +
+		"| `port` | `GONFIG_PORT` | `--port` | int | `8080` | Port of the server. |\n"
+	assert.Equal(t, expected, string(content))
+}
+
+func TestMarkdown_escape(t *testing.T) {
+	content, err := Markdown(&struct {
+		Labels map[string]string `description:"Labels | tags.\nOne per line."`
+	}{}, Opts{})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "| `labels.<name>` | `GONFIG_LABELS_<NAME>` | `--labels.<name>` | map |  | Labels \\| tags.<br>One per line. |\n")
+}
+
+func TestHTML(t *testing.T) {
+	content, err := HTML(&struct {
+		Labels map[string]string `description:"Labels <key>=<value>."`
+	}{}, Opts{})
+	require.NoError(t, err)
+
+	expected := `<table>
+  <thead>
+    <tr>
+      <th>Key</th>
+      <th>Environment variable</th>
+      <th>Flag</th>
+      <th>Type</th>
+      <th>Default</th>
+      <th>Description</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td><code>labels.&lt;name&gt;</code></td>
+      <td><code>GONFIG_LABELS_&lt;NAME&gt;</code></td>
+      <td><code>--labels.&lt;name&gt;</code></td>
+      <td>map</td>
+      <td></td>
+      <td>Labels &lt;key&gt;=&lt;value&gt;.</td>
+    </tr>
+  </tbody>
+</table>
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestOptions_unsupportedElement(t *testing.T) {
+	_, err := Options(config{}, Opts{})
+	require.EqualError(t, err, "unsupported element type: docs.config")
+}
+
+func TestOptions_aliases(t *testing.T) {
+	element := &struct {
+		Verbose bool `flag:",short=v" description:"Verbose output."`
+		Server  struct {
+			Host string `flag:"host,short=H" description:"Host of the server."`
+			Port int    `description:"Port of the server."`
+		}
+	}{}
+
+	options, err := Options(element, Opts{EnvPrefix: "MYAPP_"})
+	require.NoError(t, err)
+
+	expected := []Option{
+		{Key: "verbose", Env: "MYAPP_VERBOSE", Flag: "-v, --verbose", Type: "bool", Description: "Verbose output."},
+		{Key: "server.host", Env: "MYAPP_SERVER_HOST", Flag: "-H, --host", Type: "string", Description: "Host of the server."},
+		{Key: "server.port", Env: "MYAPP_SERVER_PORT", Flag: "--server.port", Type: "int", Description: "Port of the server."},
+	}
+	assert.Equal(t, expected, options)
+}
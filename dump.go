@@ -0,0 +1,126 @@
+package gonfig
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crazy-max/gonfig/env"
+	"github.com/crazy-max/gonfig/file"
+	"github.com/crazy-max/gonfig/parser"
+	"github.com/crazy-max/gonfig/types"
+)
+
+// DumpOpts holds options used when dumping a configuration.
+type DumpOpts struct {
+	// Format of the output: "yaml" (or "yml"), "json", "toml" or "env".
+	Format string
+	// EnvPrefix is the prefix of the environment variables in the "env" format. Default to "GONFIG_".
+	EnvPrefix string
+	// Mask replaces the secret values. Default to types.SecretMask.
+	Mask string
+}
+
+// Dump renders the configuration cfg in the given format ("yaml", "json", "toml" or "env").
+// The values of the fields tagged with secret:"true" or of type types.Secret are masked.
+func Dump(cfg interface{}, format string) ([]byte, error) {
+	return DumpWithOpts(cfg, DumpOpts{Format: format})
+}
+
+// DumpWithOpts renders the configuration cfg using opts.
+func DumpWithOpts(cfg interface{}, opts DumpOpts) ([]byte, error) {
+	if opts.Mask == "" {
+		opts.Mask = types.SecretMask
+	}
+	if opts.EnvPrefix == "" {
+		opts.EnvPrefix = env.DefaultNamePrefix
+	}
+
+	// the values of the secret fields and of their children are masked
+	encodeOpts := file.EncodeOpts{
+		OnValue: func(leaf file.Leaf) interface{} {
+			if leaf.Value.IsValid() && !leaf.Value.IsZero() {
+				for _, field := range leaf.Fields {
+					if isSecret(field) {
+						return opts.Mask
+					}
+				}
+			}
+			return leaf.Encoded
+		},
+	}
+
+	switch strings.ToLower(opts.Format) {
+	case "yaml", "yml", "json", "toml":
+		b, err := file.EncodeWithOpts(cfg, "."+opts.Format, encodeOpts)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(opts.Format, "json") {
+			b = bytes.TrimSuffix(b, []byte("\n"))
+		}
+		return b, nil
+	case "env":
+		tree, err := file.EncodeToMap(cfg, encodeOpts)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		writeEnv(&buf, strings.TrimSuffix(opts.EnvPrefix, "_"), tree)
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported dump format: %s", opts.Format)
+	}
+}
+
+func isSecret(field reflect.StructField) bool {
+	if secret, _ := strconv.ParseBool(field.Tag.Get(parser.TagSecret)); secret {
+		return true
+	}
+
+	fType := field.Type
+	for fType.Kind() == reflect.Pointer || fType.Kind() == reflect.Slice || fType.Kind() == reflect.Map {
+		fType = fType.Elem()
+	}
+
+	return fType == reflect.TypeOf(types.Secret(""))
+}
+
+func writeEnv(buf *bytes.Buffer, name string, value interface{}) {
+	switch v := value.(type) {
+	case *file.OrderedMap:
+		v.Range(func(key string, value interface{}) {
+			writeEnv(buf, name+"_"+strings.ToUpper(key), value)
+		})
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			writeEnv(buf, name+"_"+strings.ToUpper(key), v[key])
+		}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case *file.OrderedMap, map[string]interface{}:
+				writeEnv(buf, fmt.Sprintf("%s[%d]", name, i), item)
+			default:
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		if len(values) > 0 {
+			_, _ = fmt.Fprintf(buf, "%s=%s\n", name, strings.Join(values, ","))
+		}
+	case nil:
+		// empty lists and values that can't be encoded are omitted, as in files
+	default:
+		_, _ = fmt.Fprintf(buf, "%s=%v\n", name, v)
+	}
+}
@@ -0,0 +1,186 @@
+package gonfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dumpedConfig struct {
+	Name     string
+	APIKey   types.Secret
+	Timeout  types.Duration
+	Server   *dumpedServer
+	Backends []dumpedBackend
+	Hooks    map[string]*dumpedHook
+	Extra    map[string]interface{} `secret:"true"`
+	Ignored  string                 `file:"-"`
+}
+
+type dumpedServer struct {
+	Host     string
+	Password string `secret:"true"`
+	Ports    []int
+}
+
+type dumpedBackend struct {
+	URL   string
+	Token string `secret:"true"`
+}
+
+type dumpedHook struct {
+	Endpoint string
+	Secret   types.Secret
+}
+
+func newDumpedConfig() *dumpedConfig {
+	return &dumpedConfig{
+		Name:    "myapp",
+		APIKey:  "abcdef",
+		Timeout: types.Duration(5 * time.Second),
+		Server: &dumpedServer{
+			Host:     "localhost",
+			Password: "password",
+			Ports:    []int{80, 443},
+		},
+		Backends: []dumpedBackend{
+			{URL: "http://foo", Token: "token"},
+		},
+		Hooks: map[string]*dumpedHook{
+			"slack": {Endpoint: "http://slack", Secret: "s3cr3t"},
+		},
+		Extra:   map[string]interface{}{"foo": "bar"},
+		Ignored: "ignored",
+	}
+}
+
+func TestDump(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: "yaml",
+			expected: `name: myapp
+apiKey: '********'
+timeout: 5s
+server:
+  host: localhost
+  password: '********'
+  ports:
+    - 80
+    - 443
+backends:
+  - url: http://foo
+    token: '********'
+hooks:
+  slack:
+    endpoint: http://slack
+    secret: '********'
+extra: '********'
+`,
+		},
+		{
+			format: "json",
+			expected: `{
+  "name": "myapp",
+  "apiKey": "********",
+  "timeout": "5s",
+  "server": {
+    "host": "localhost",
+    "password": "********",
+    "ports": [
+      80,
+      443
+    ]
+  },
+  "backends": [
+    {
+      "url": "http://foo",
+      "token": "********"
+    }
+  ],
+  "hooks": {
+    "slack": {
+      "endpoint": "http://slack",
+      "secret": "********"
+    }
+  },
+  "extra": "********"
+}`,
+		},
+		{
+			format: "toml",
+			expected: `apiKey = "********"
+extra = "********"
+name = "myapp"
+timeout = "5s"
+
+[[backends]]
+  token = "********"
+  url = "http://foo"
+
+[hooks]
+  [hooks.slack]
+    endpoint = "http://slack"
+    secret = "********"
+
+[server]
+  host = "localhost"
+  password = "********"
+  ports = [80, 443]
+`,
+		},
+		{
+			format: "env",
+			expected: `GONFIG_NAME=myapp
+GONFIG_APIKEY=********
+GONFIG_TIMEOUT=5s
+GONFIG_SERVER_HOST=localhost
+GONFIG_SERVER_PASSWORD=********
+GONFIG_SERVER_PORTS=80,443
+GONFIG_BACKENDS[0]_URL=http://foo
+GONFIG_BACKENDS[0]_TOKEN=********
+GONFIG_HOOKS_SLACK_ENDPOINT=http://slack
+GONFIG_HOOKS_SLACK_SECRET=********
+GONFIG_EXTRA=********
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.format, func(t *testing.T) {
+			b, err := Dump(newDumpedConfig(), test.format)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(b))
+		})
+	}
+}
+
+func TestDumpWithOpts(t *testing.T) {
+	cfg := &dumpedConfig{
+		Name:   "myapp",
+		Extra:  map[string]interface{}{"foo": "bar"},
+		Server: &dumpedServer{Password: "password"},
+	}
+
+	b, err := DumpWithOpts(cfg, DumpOpts{Format: "env", EnvPrefix: "MYAPP_", Mask: "xxx"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `MYAPP_NAME=myapp
+MYAPP_APIKEY=
+MYAPP_TIMEOUT=0s
+MYAPP_SERVER_HOST=
+MYAPP_SERVER_PASSWORD=xxx
+MYAPP_EXTRA=xxx
+`, string(b))
+}
+
+func TestDump_errors(t *testing.T) {
+	_, err := Dump(newDumpedConfig(), "xml")
+	require.Error(t, err)
+}
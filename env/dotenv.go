@@ -0,0 +1,340 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadDotEnv reads the variables defined in the .env file filename
+// and returns them in the format of os.Environ, without setting them.
+// See ParseDotEnv for the supported syntax.
+func ReadDotEnv(filename string) ([]string, error) {
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	environ, err := ParseDotEnv(string(content), os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return environ, nil
+}
+
+// ParseDotEnv parses the content of a .env file and returns the variables in the format of os.Environ.
+// The syntax is:
+//   - one KEY=value per line, optionally prefixed with export
+//   - blank lines and lines starting with # are ignored, as well as the end of unquoted values after " #"
+//   - single-quoted values are kept as is and can span several lines
+//   - double-quoted values can span several lines and support the \n, \r, \t, \\, \" and \$ escapes
+//   - ${VAR} and $VAR references in unquoted and double-quoted values are replaced by the value of VAR
+//     defined earlier in the content, or returned by lookup otherwise
+func ParseDotEnv(content string, lookup func(string) (string, bool)) ([]string, error) {
+	p := &dotEnvParser{
+		content: strings.ReplaceAll(content, "\r\n", "\n"),
+		line:    1,
+		vars:    make(map[string]string),
+		lookup:  lookup,
+	}
+
+	var environ []string
+	for {
+		key, value, ok, err := p.next()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.line, err)
+		}
+		if !ok {
+			return environ, nil
+		}
+
+		p.vars[key] = value
+		environ = append(environ, key+"="+value)
+	}
+}
+
+type dotEnvParser struct {
+	content string
+	pos     int
+	line    int
+	vars    map[string]string
+	lookup  func(string) (string, bool)
+}
+
+// next returns the next variable, or ok false at the end of the content.
+func (p *dotEnvParser) next() (key, value string, ok bool, err error) {
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.content) {
+			return "", "", false, nil
+		}
+
+		switch p.content[p.pos] {
+		case '\n':
+			p.pos++
+			p.line++
+			continue
+		case '#':
+			p.skipLine()
+			continue
+		}
+		break
+	}
+
+	key = p.readKey()
+	if key == "export" {
+		p.skipSpaces()
+		if p.pos < len(p.content) && p.content[p.pos] != '=' {
+			key = p.readKey()
+		}
+	}
+
+	if key == "" {
+		return "", "", false, fmt.Errorf("invalid variable name")
+	}
+
+	p.skipSpaces()
+	if p.pos >= len(p.content) || p.content[p.pos] != '=' {
+		return "", "", false, fmt.Errorf("missing = after %s", key)
+	}
+	p.pos++
+	p.skipSpaces()
+
+	if p.pos < len(p.content) {
+		switch p.content[p.pos] {
+		case '\'':
+			value, err = p.readSingleQuoted()
+		case '"':
+			value, err = p.readDoubleQuoted()
+		default:
+			value = p.expand(p.readUnquoted())
+		}
+		if err != nil {
+			return "", "", false, err
+		}
+	}
+
+	if err = p.endOfLine(); err != nil {
+		return "", "", false, err
+	}
+
+	return key, value, true, nil
+}
+
+func (p *dotEnvParser) readKey() string {
+	start := p.pos
+	for p.pos < len(p.content) && isNameChar(p.content[p.pos], p.pos == start) {
+		p.pos++
+	}
+	return p.content[start:p.pos]
+}
+
+func (p *dotEnvParser) readSingleQuoted() (string, error) {
+	line := p.line
+	p.pos++
+
+	start := p.pos
+	for p.pos < len(p.content) && p.content[p.pos] != '\'' {
+		if p.content[p.pos] == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+
+	if p.pos >= len(p.content) {
+		return "", fmt.Errorf("unterminated single-quoted value starting on line %d", line)
+	}
+
+	value := p.content[start:p.pos]
+	p.pos++
+
+	return value, nil
+}
+
+func (p *dotEnvParser) readDoubleQuoted() (string, error) {
+	line := p.line
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.content) {
+		c := p.content[p.pos]
+
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.content):
+			p.pos++
+			switch p.content[p.pos] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '\\', '"', '$':
+				b.WriteByte(p.content[p.pos])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(p.content[p.pos])
+			}
+			p.pos++
+		case c == '$':
+			name, n := readReference(p.content[p.pos:])
+			if n == 0 {
+				b.WriteByte(c)
+				p.pos++
+				continue
+			}
+			b.WriteString(p.get(name))
+			p.pos += n
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", fmt.Errorf("unterminated double-quoted value starting on line %d", line)
+}
+
+func (p *dotEnvParser) readUnquoted() string {
+	start := p.pos
+	for p.pos < len(p.content) && p.content[p.pos] != '\n' {
+		if p.content[p.pos] == '#' && p.pos > start && isSpace(p.content[p.pos-1]) {
+			break
+		}
+		p.pos++
+	}
+
+	value := strings.TrimSpace(p.content[start:p.pos])
+	p.skipLine()
+
+	return value
+}
+
+// endOfLine checks that only spaces or a comment follow a value.
+func (p *dotEnvParser) endOfLine() error {
+	p.skipSpaces()
+	if p.pos >= len(p.content) {
+		return nil
+	}
+
+	switch p.content[p.pos] {
+	case '\n':
+		p.pos++
+		p.line++
+		return nil
+	case '#':
+		p.skipLine()
+		return nil
+	default:
+		return fmt.Errorf("unexpected character %q after value", p.content[p.pos])
+	}
+}
+
+func (p *dotEnvParser) skipSpaces() {
+	for p.pos < len(p.content) && isSpace(p.content[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *dotEnvParser) skipLine() {
+	for p.pos < len(p.content) && p.content[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+// expand replaces the ${VAR} and $VAR references in s.
+func (p *dotEnvParser) expand(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		name, n := readReference(s[i:])
+		if n == 0 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		b.WriteString(p.get(name))
+		i += n - 1
+	}
+
+	return b.String()
+}
+
+func (p *dotEnvParser) get(name string) string {
+	if value, ok := p.vars[name]; ok {
+		return value
+	}
+	if p.lookup != nil {
+		value, _ := p.lookup(name)
+		return value
+	}
+	return ""
+}
+
+// readReference reads the ${VAR} or $VAR reference at the start of s
+// and returns the name of the variable and the length of the reference, 0 if there is none.
+func readReference(s string) (string, int) {
+	if len(s) < 2 {
+		return "", 0
+	}
+
+	if s[1] == '{' {
+		end := strings.IndexByte(s, '}')
+		if end < 0 || !isName(s[2:end]) {
+			return "", 0
+		}
+		return s[2:end], end + 1
+	}
+
+	n := 1
+	for n < len(s) && isNameChar(s[n], n == 1) && s[n] != '.' {
+		n++
+	}
+	if n == 1 {
+		return "", 0
+	}
+
+	return s[1:n], n
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameChar(s[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9', c == '.':
+		return !first
+	default:
+		return false
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
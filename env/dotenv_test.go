@@ -0,0 +1,159 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected []string
+	}{
+		{
+			desc:     "empty",
+			content:  "",
+			expected: nil,
+		},
+		{
+			desc: "simple values",
+			content: `
+MYAPP_FOO=foo
+MYAPP_BAR = bar
+MYAPP_EMPTY=
+`,
+			expected: []string{"MYAPP_FOO=foo", "MYAPP_BAR=bar", "MYAPP_EMPTY="},
+		},
+		{
+			desc: "comments",
+			content: `# comment
+  # indented comment
+MYAPP_FOO=foo # inline comment
+MYAPP_BAR=bar#baz
+MYAPP_BAZ='baz' # comment after quotes
+`,
+			expected: []string{"MYAPP_FOO=foo", "MYAPP_BAR=bar#baz", "MYAPP_BAZ=baz"},
+		},
+		{
+			desc: "export prefix",
+			content: `export MYAPP_FOO=foo
+export  MYAPP_BAR="bar"
+export=1
+`,
+			expected: []string{"MYAPP_FOO=foo", "MYAPP_BAR=bar", "export=1"},
+		},
+		{
+			desc: "single quotes",
+			content: `MYAPP_FOO='${HOME} \n # not a comment'
+MYAPP_BAR='line1
+line2'
+`,
+			expected: []string{`MYAPP_FOO=${HOME} \n # not a comment`, "MYAPP_BAR=line1\nline2"},
+		},
+		{
+			desc: "double quotes",
+			content: `MYAPP_FOO="foo \"bar\"\tbaz\n\\ \$HOME \x"
+MYAPP_BAR="line1
+line2"
+`,
+			expected: []string{"MYAPP_FOO=foo \"bar\"\tbaz\n\\ $HOME \\x", "MYAPP_BAR=line1\nline2"},
+		},
+		{
+			desc: "references",
+			content: `MYAPP_HOST=localhost
+MYAPP_URL=http://${MYAPP_HOST}:$MYAPP_PORT/$EXTERNAL
+MYAPP_QUOTED="${MYAPP_HOST}.${UNKNOWN}"
+MYAPP_PRICE=$5 ${
+MYAPP_HOST=example.com
+`,
+			expected: []string{
+				"MYAPP_HOST=localhost",
+				"MYAPP_URL=http://localhost:/external",
+				"MYAPP_QUOTED=localhost.",
+				"MYAPP_PRICE=$5 ${",
+				"MYAPP_HOST=example.com",
+			},
+		},
+		{
+			desc:     "windows line endings",
+			content:  "MYAPP_FOO=foo\r\nMYAPP_BAR=\"a\r\nb\"\r\n",
+			expected: []string{"MYAPP_FOO=foo", "MYAPP_BAR=a\nb"},
+		},
+	}
+
+	lookup := func(name string) (string, bool) {
+		if name == "EXTERNAL" {
+			return "external", true
+		}
+		return "", false
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			environ, err := ParseDotEnv(test.content, lookup)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, environ)
+		})
+	}
+}
+
+func TestParseDotEnv_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "missing equal",
+			content:  "MYAPP_FOO=foo\nMYAPP_BAR\n",
+			expected: "line 2: missing = after MYAPP_BAR",
+		},
+		{
+			desc:     "invalid name",
+			content:  "1FOO=foo\n",
+			expected: "line 1: invalid variable name",
+		},
+		{
+			desc:     "unterminated single quote",
+			content:  "MYAPP_FOO='foo\n\n",
+			expected: "line 3: unterminated single-quoted value starting on line 1",
+		},
+		{
+			desc:     "unterminated double quote",
+			content:  "\nMYAPP_FOO=\"foo",
+			expected: "line 2: unterminated double-quoted value starting on line 2",
+		},
+		{
+			desc:     "characters after quotes",
+			content:  "MYAPP_FOO=\"foo\"bar\n",
+			expected: `line 1: unexpected character 'b' after value`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := ParseDotEnv(test.content, nil)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestReadDotEnv(t *testing.T) {
+	t.Setenv("GONFIG_TEST_DOTENV", "bar")
+
+	filename := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(filename, []byte("MYAPP_FOO=${GONFIG_TEST_DOTENV}\n"), 0o644))
+
+	environ, err := ReadDotEnv(filename)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MYAPP_FOO=bar"}, environ)
+
+	_, ok := os.LookupEnv("MYAPP_FOO")
+	assert.False(t, ok)
+}
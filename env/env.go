@@ -3,8 +3,12 @@ package env
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/crazy-max/gonfig/parser"
 )
@@ -12,28 +16,79 @@ import (
 // DefaultNamePrefix is the default prefix for environment variable names.
 const DefaultNamePrefix = "GONFIG_"
 
+const fileSuffix = ".file"
+
 // Decode decodes the given environment variables into the given element.
+// A variable named after a leaf value with a _FILE suffix (e.g. GONFIG_PASSWORD_FILE)
+// sets the value to the content of the file it points to, without the trailing newline.
 // The operation goes through four stages roughly summarized as:
 // env vars -> map
 // map -> tree of untyped nodes
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func Decode(environ []string, prefix string, element interface{}) error {
+	return DecodeWithOpts(environ, prefix, element, DecodeOpts{})
+}
+
+// DecodeOpts holds options used when decoding environment variables.
+type DecodeOpts struct {
+	// OnFill is called for each leaf value set into the element,
+	// with its dotted path and the name of the environment variable it comes from.
+	OnFill func(path, name string)
+}
+
+// DecodeWithOpts decodes the given environment variables into the given element using opts.
+func DecodeWithOpts(environ []string, prefix string, element interface{}, opts DecodeOpts) error {
 	if err := checkPrefix(prefix); err != nil {
 		return err
 	}
 
+	rootName := strings.ToLower(prefix[:len(prefix)-1])
+
 	vars := make(map[string]string)
+	names := make(map[string]string)
+	fileKeys := make(map[string]bool)
 	for _, evr := range environ {
 		k, v, _ := strings.Cut(evr, "=")
-		if strings.HasPrefix(strings.ToUpper(k), prefix) {
-			key := strings.ReplaceAll(strings.ToLower(k), "_", ".")
-			vars[key] = v
+		if !strings.HasPrefix(strings.ToUpper(k), prefix) {
+			continue
+		}
+
+		key := strings.ReplaceAll(strings.ToLower(k), "_", ".")
+
+		fromFile := isFileVar(element, rootName, key)
+		if fromFile {
+			key = strings.TrimSuffix(key, fileSuffix)
+
+			content, err := os.ReadFile(filepath.Clean(v))
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", k, err)
+			}
+			v = strings.TrimRight(string(content), "\r\n")
 		}
+
+		if name, ok := names[key]; ok && (fromFile || fileKeys[key]) {
+			return fmt.Errorf("both %s and %s are set", name, k)
+		}
+
+		vars[key] = v
+		names[key] = k
+		fileKeys[key] = fromFile
 	}
 
-	rootName := strings.ToLower(prefix[:len(prefix)-1])
-	return parser.Decode(vars, element, rootName)
+	var decodeOpts parser.DecodeOpts
+	if opts.OnFill != nil {
+		decodeOpts.OnFill = func(path, key string) {
+			key = rootName + "." + strings.ToLower(key)
+			name, ok := names[key]
+			if !ok {
+				name = strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+			}
+			opts.OnFill(path, name)
+		}
+	}
+
+	return parser.DecodeWithOpts(vars, element, rootName, decodeOpts)
 }
 
 // Encode encodes the configuration in element into the environment variables represented in the returned Flats.
@@ -81,3 +136,65 @@ func checkPrefix(prefix string) error {
 
 	return nil
 }
+
+// isFileVar reports whether key is a _FILE variable of a leaf value of element.
+func isFileVar(element interface{}, rootName, key string) bool {
+	if element == nil || !strings.HasSuffix(key, fileSuffix) {
+		return false
+	}
+
+	path := strings.Split(strings.TrimPrefix(key, rootName+"."), ".")
+	rType := reflect.TypeOf(element)
+
+	return !isLeaf(rType, path) && isLeaf(rType, path[:len(path)-1])
+}
+
+func isLeaf(rType reflect.Type, path []string) bool {
+	for rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+
+	if len(path) == 0 {
+		switch rType.Kind() {
+		case reflect.Struct:
+			return rType == reflect.TypeOf(time.Time{})
+		case reflect.Map:
+			return false
+		case reflect.Slice:
+			elem := rType.Elem()
+			return elem.Kind() != reflect.Struct && (elem.Kind() != reflect.Pointer || elem.Elem().Kind() != reflect.Struct)
+		default:
+			return true
+		}
+	}
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		for i := 0; i < rType.NumField(); i++ {
+			field := rType.Field(i)
+			if !parser.IsExported(field) {
+				continue
+			}
+
+			if field.Anonymous {
+				if isLeaf(field.Type, path) {
+					return true
+				}
+				continue
+			}
+
+			name, fType := field.Name, field.Type
+			if sliceName := field.Tag.Get(parser.TagLabelSliceAsStruct); sliceName != "" && fType.Kind() == reflect.Slice {
+				name, fType = sliceName, fType.Elem()
+			}
+
+			if strings.EqualFold(name, path[0]) {
+				return isLeaf(fType, path[1:])
+			}
+		}
+	case reflect.Map:
+		return isLeaf(rType.Elem(), path[1:])
+	}
+
+	return false
+}
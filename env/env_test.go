@@ -1,6 +1,8 @@
 package env
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/crazy-max/gonfig/generator"
@@ -181,6 +183,88 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecode_file(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("s3cr3t\n"), 0o600))
+
+	type element struct {
+		Password string
+		Sources  []string
+		Server   *struct {
+			FTP *struct {
+				Password string
+			}
+		}
+		Log struct {
+			File string
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		environ  []string
+		expected *element
+		wantErr  string
+	}{
+		{
+			desc:     "root field",
+			environ:  []string{"GONFIG_PASSWORD_FILE=" + secret},
+			expected: &element{Password: "s3cr3t"},
+		},
+		{
+			desc:     "slice field",
+			environ:  []string{"GONFIG_SOURCES_FILE=" + secret},
+			expected: &element{Sources: []string{"s3cr3t"}},
+		},
+		{
+			desc:    "nested field",
+			environ: []string{"GONFIG_SERVER_FTP_PASSWORD_FILE=" + secret},
+			expected: &element{Server: &struct {
+				FTP *struct {
+					Password string
+				}
+			}{FTP: &struct {
+				Password string
+			}{Password: "s3cr3t"}}},
+		},
+		{
+			desc:     "field named file",
+			environ:  []string{"GONFIG_LOG_FILE=" + secret},
+			expected: &element{Log: struct{ File string }{File: secret}},
+		},
+		{
+			desc:    "plain and file variables",
+			environ: []string{"GONFIG_PASSWORD=foo", "GONFIG_PASSWORD_FILE=" + secret},
+			wantErr: "both GONFIG_PASSWORD and GONFIG_PASSWORD_FILE are set",
+		},
+		{
+			desc:    "file and plain variables",
+			environ: []string{"GONFIG_PASSWORD_FILE=" + secret, "GONFIG_PASSWORD=foo"},
+			wantErr: "both GONFIG_PASSWORD_FILE and GONFIG_PASSWORD are set",
+		},
+		{
+			desc:    "missing file",
+			environ: []string{"GONFIG_PASSWORD_FILE=/does/not/exist"},
+			wantErr: "failed to read GONFIG_PASSWORD_FILE",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			elem := &element{}
+
+			err := Decode(test.environ, DefaultNamePrefix, elem)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, elem)
+		})
+	}
+}
+
 func TestEncode(t *testing.T) {
 	element := &Ya{
 		Foo: &Yaa{
@@ -31,6 +31,12 @@ func TestFindPrefixedEnvVars(t *testing.T) {
 			element:  &Yo{},
 			expected: nil,
 		},
+		{
+			desc:     "file suffix",
+			environ:  []string{"GONFIG_FOO_FILE=/run/secrets/foo"},
+			element:  &Yo{},
+			expected: []string{"GONFIG_FOO_FILE=/run/secrets/foo"},
+		},
 		{
 			desc:     "filter",
 			environ:  []string{"GONFIG_NOPE", "GONFIG_NO", "GONFIG_FOO", "GONFIG_FII01"},
@@ -0,0 +1,387 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/crazy-max/gonfig/parser"
+	"github.com/crazy-max/gonfig/types"
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeOpts holds options used when encoding.
+type EncodeOpts struct {
+	// OnValue is called for each leaf value and returns the value to encode instead of leaf.Encoded.
+	OnValue func(leaf Leaf) interface{}
+}
+
+// Leaf is a leaf value of an encoded element: a scalar, a list of scalars or a raw value.
+type Leaf struct {
+	// Fields are the struct fields leading to the value, from the root.
+	Fields []reflect.StructField
+	// Value is the value in the element.
+	Value reflect.Value
+	// Encoded is the encoded value: a string, an int64, a uint64, a float64, a bool,
+	// a []interface{} of them, a raw value, or nil if the value can't be encoded.
+	Encoded interface{}
+}
+
+// Encode encodes the given element in the format of the file extension: .toml, .yml, .yaml, .json, .jsonc or .json5.
+// The operation goes through two stages roughly summarized as:
+// typed element -> tree of untyped nodes
+// untyped nodes -> file contents.
+// Decoding the result gives back the element.
+func Encode(element interface{}, extension string) ([]byte, error) {
+	return EncodeWithOpts(element, extension, EncodeOpts{})
+}
+
+// EncodeWithOpts encodes the given element in the format of the file extension using opts.
+func EncodeWithOpts(element interface{}, extension string, opts EncodeOpts) ([]byte, error) {
+	tree, err := EncodeToMap(element, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(extension) {
+	case ".toml":
+		var buf bytes.Buffer
+		if err = toml.NewEncoder(&buf).Encode(tree.raw()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	case ".yml", ".yaml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(tree.yamlNode()); err != nil {
+			return nil, err
+		}
+		if err = encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	case ".json", ".jsonc", ".json5":
+		content, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", extension)
+	}
+}
+
+// EncodeToFile encodes the given element into the file filePath, in the format of its extension.
+func EncodeToFile(filePath string, element interface{}) error {
+	content, err := Encode(element, filepath.Ext(filePath))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, content, 0o600)
+}
+
+// EncodeToMap encodes the given element, which must be a pointer to a struct, to an OrderedMap
+// whose keys are the ones written in files.
+func EncodeToMap(element interface{}, opts EncodeOpts) (*OrderedMap, error) {
+	node, err := parser.EncodeToNode(element, parser.DefaultRootName, parser.EncoderToNodeOpts{TagName: parser.TagFile})
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ := encoder{EncodeOpts: opts}.value(node, reflect.ValueOf(element), nil)
+
+	tree, ok := value.(*OrderedMap)
+	if !ok {
+		return nil, fmt.Errorf("unsupported element type: %T", element)
+	}
+
+	return tree, nil
+}
+
+type encoder struct {
+	EncodeOpts
+}
+
+// value converts a node and its matching value to a tree made of *OrderedMap, []interface{} and scalars,
+// and reports whether the value is zero. fields are the struct fields leading to the value.
+func (e encoder) value(node *parser.Node, rValue reflect.Value, fields []reflect.StructField) (interface{}, bool) {
+	for rValue.Kind() == reflect.Pointer || rValue.Kind() == reflect.Interface {
+		if rValue.IsNil() {
+			break
+		}
+		rValue = rValue.Elem()
+	}
+
+	if node.RawValue != nil {
+		return e.leaf(fields, rValue, node.RawValue)
+	}
+
+	if len(node.Children) == 0 {
+		if rValue.Kind() == reflect.Struct && rValue.Type() != reflect.TypeOf(time.Time{}) ||
+			rValue.Kind() == reflect.Pointer {
+			// allowEmpty
+			return &OrderedMap{}, false
+		}
+		return e.leaf(fields, rValue, EncodeScalar(rValue))
+	}
+
+	if rValue.Kind() == reflect.Slice {
+		var list []interface{}
+		zero := true
+		for i, child := range node.Children {
+			item, itemZero := e.value(child, rValue.Index(i), fields)
+			list = append(list, item)
+			zero = zero && itemZero
+		}
+		return list, zero
+	}
+
+	children := node.Children
+	if rValue.Kind() == reflect.Map {
+		children = make([]*parser.Node, len(node.Children))
+		copy(children, node.Children)
+		sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	}
+
+	m := &OrderedMap{}
+	for _, child := range children {
+		switch rValue.Kind() {
+		case reflect.Map:
+			value, zero := e.value(child, rValue.MapIndex(reflect.ValueOf(child.FieldName)), fields)
+			m.add(child.Name, child.Description, value, zero)
+		case reflect.Struct:
+			field, _ := rValue.Type().FieldByName(child.FieldName)
+			value, zero := e.value(child, rValue.FieldByName(child.FieldName), append(fields[:len(fields):len(fields)], field))
+			m.add(KeyName(child.Name), child.Description, value, zero)
+		}
+	}
+
+	return m, m.zero()
+}
+
+// leaf returns the encoded leaf value, a raw map being converted to an OrderedMap.
+func (e encoder) leaf(fields []reflect.StructField, rValue reflect.Value, value interface{}) (interface{}, bool) {
+	zero := !rValue.IsValid() || rValue.IsZero() || rValue.Kind() == reflect.Slice && rValue.Len() == 0
+
+	if e.OnValue != nil {
+		value = e.OnValue(Leaf{Fields: fields, Value: rValue, Encoded: value})
+	}
+
+	if raw, ok := value.(map[string]interface{}); ok {
+		m := rawMap(raw)
+		return m, m.zero()
+	}
+
+	return value, zero
+}
+
+func rawMap(raw map[string]interface{}) *OrderedMap {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	m := &OrderedMap{}
+	for _, key := range keys {
+		switch v := raw[key].(type) {
+		case map[string]interface{}:
+			sub := rawMap(v)
+			m.add(key, "", sub, sub.zero())
+		default:
+			m.add(key, "", v, v == nil)
+		}
+	}
+	return m
+}
+
+// EncodeScalar encodes a scalar value or a list of scalar values as written in files.
+// Durations are written as strings (e.g. 1m30s), and it returns nil for an empty list or an unsupported value.
+func EncodeScalar(rValue reflect.Value) interface{} {
+	switch rValue.Kind() {
+	case reflect.String:
+		return rValue.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch rValue.Type() {
+		case reflect.TypeOf(types.Duration(0)), reflect.TypeOf(time.Duration(0)):
+			return time.Duration(rValue.Int()).String()
+		}
+		return rValue.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rValue.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float()
+	case reflect.Bool:
+		return rValue.Bool()
+	case reflect.Slice:
+		if rValue.Len() == 0 {
+			return nil
+		}
+		list := make([]interface{}, 0, rValue.Len())
+		for i := 0; i < rValue.Len(); i++ {
+			list = append(list, EncodeScalar(rValue.Index(i)))
+		}
+		return list
+	case reflect.Struct:
+		if t, ok := rValue.Interface().(time.Time); ok {
+			return t.Format(time.RFC3339Nano)
+		}
+	}
+
+	return nil
+}
+
+// KeyName returns the key of a field name as written in files:
+// the leading upper case letters are lowered, except the last one if it starts a word (e.g. URLPath -> urlPath).
+func KeyName(name string) string {
+	runes := []rune(name)
+
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// OrderedMap is an encoded map that keeps the order of its keys.
+// Its values are *OrderedMap, []interface{} and scalars.
+type OrderedMap struct {
+	entries []mapEntry
+}
+
+type mapEntry struct {
+	key         string
+	description string
+	value       interface{}
+	// zero reports whether the value is the zero value of its type.
+	zero bool
+}
+
+func (m *OrderedMap) add(key, description string, value interface{}, zero bool) {
+	m.entries = append(m.entries, mapEntry{key: key, description: description, value: value, zero: zero})
+}
+
+// zero reports whether all the values of a non-empty map are zero.
+func (m *OrderedMap) zero() bool {
+	for _, e := range m.entries {
+		if !e.zero {
+			return false
+		}
+	}
+	return len(m.entries) > 0
+}
+
+// Range calls f for each key and value of the map, in order.
+// A nil value is a value that can't be encoded, and is omitted from the files.
+func (m *OrderedMap) Range(f func(key string, value interface{})) {
+	for _, e := range m.entries {
+		f(e.key, e.value)
+	}
+}
+
+// MarshalJSON writes the map keeping the order of its keys.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for _, e := range m.entries {
+		if e.value == nil {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(e.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		v, err := json.Marshal(e.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *OrderedMap) yamlNode() *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, e := range m.entries {
+		if e.value != nil {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: e.key}, toYAMLNode(e.value))
+		}
+	}
+	return node
+}
+
+func toYAMLNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case *OrderedMap:
+		return v.yamlNode()
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			node.Content = append(node.Content, toYAMLNode(item))
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: fmt.Sprint(v)}
+		}
+		return node
+	}
+}
+
+func (m *OrderedMap) raw() map[string]interface{} {
+	result := make(map[string]interface{}, len(m.entries))
+	for _, e := range m.entries {
+		if e.value != nil {
+			result[e.key] = toRaw(e.value)
+		}
+	}
+	return result
+}
+
+func toRaw(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *OrderedMap:
+		return v.raw()
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = toRaw(item)
+		}
+		return list
+	default:
+		return v
+	}
+}
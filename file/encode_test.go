@@ -0,0 +1,217 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encodedServer struct {
+	Host string
+	Port int
+}
+
+type encodedEmpty struct {
+	Enabled bool
+}
+
+type encodedConfig struct {
+	Name     string
+	Ignored  string `file:"-"`
+	Debug    bool
+	Ratio    float64
+	MaxSize  uint64
+	Timeout  types.Duration
+	Interval time.Duration
+	Sources  []string
+	Ports    []int
+	Empty    *encodedEmpty `file:"allowEmpty"`
+	Missing  *encodedEmpty
+	Server   *encodedServer
+	Servers  []encodedServer
+	Notif    map[string]*encodedServer
+	Labels   map[string]string
+	Raw      map[string]interface{}
+}
+
+func TestEncode(t *testing.T) {
+	element := &encodedConfig{
+		Name:     "myapp",
+		Ignored:  "foo",
+		Ratio:    1.5,
+		MaxSize:  1 << 40,
+		Timeout:  types.Duration(90 * time.Second),
+		Interval: 500 * time.Millisecond,
+		Sources:  []string{"/", "/tmp"},
+		Ports:    []int{80, 443},
+		Empty:    &encodedEmpty{},
+		Server:   &encodedServer{Host: "localhost", Port: 8080},
+		Notif: map[string]*encodedServer{
+			"slack": {Host: "hooks.slack.com"},
+			"mail":  {Host: "smtp.example.com", Port: 587},
+		},
+		Labels: map[string]string{"b": "2", "a": "1"},
+		Raw: map[string]interface{}{
+			"foo": "bar",
+			"sub": map[string]interface{}{"baz": "qux"},
+		},
+	}
+
+	content, err := Encode(element, ".yml")
+	require.NoError(t, err)
+
+	expected := `name: myapp
+debug: false
+ratio: 1.5
+maxSize: 1099511627776
+timeout: 1m30s
+interval: 500ms
+sources:
+  - /
+  - /tmp
+ports:
+  - 80
+  - 443
+empty:
+  enabled: false
+server:
+  host: localhost
+  port: 8080
+notif:
+  mail:
+    host: smtp.example.com
+    port: 587
+  slack:
+    host: hooks.slack.com
+    port: 0
+labels:
+  a: "1"
+  b: "2"
+raw:
+  foo: bar
+  sub:
+    baz: qux
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestEncode_roundTrip(t *testing.T) {
+	element := &encodedConfig{
+		Name:     "myapp",
+		Debug:    true,
+		Ratio:    1.5,
+		MaxSize:  1 << 40,
+		Timeout:  types.Duration(90 * time.Second),
+		Interval: 500 * time.Millisecond,
+		Sources:  []string{"/", "/tmp"},
+		Ports:    []int{80, 443},
+		Empty:    &encodedEmpty{},
+		Server:   &encodedServer{Host: "localhost", Port: 8080},
+		Servers:  []encodedServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Notif: map[string]*encodedServer{
+			"slack": {Host: "hooks.slack.com"},
+			"mail":  {Host: "smtp.example.com", Port: 587},
+		},
+		Labels: map[string]string{"b": "2", "a": "1"},
+		Raw: map[string]interface{}{
+			"foo": "bar",
+			"sub": map[string]interface{}{"baz": "qux"},
+		},
+	}
+
+	for _, extension := range []string{".toml", ".yml", ".yaml", ".json", ".jsonc", ".json5"} {
+		t.Run(extension, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "config"+extension)
+
+			err := EncodeToFile(filePath, element)
+			require.NoError(t, err)
+
+			decoded := &encodedConfig{}
+			err = Decode(filePath, decoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, element, decoded)
+		})
+	}
+}
+
+func TestEncode_unsupportedExtension(t *testing.T) {
+	_, err := Encode(&encodedConfig{}, ".hcl")
+	require.EqualError(t, err, "unsupported file extension: .hcl")
+}
+
+func TestEncodeWithOpts(t *testing.T) {
+	element := &encodedConfig{
+		Name:    "myapp",
+		Server:  &encodedServer{Host: "localhost", Port: 8080},
+		Servers: []encodedServer{{Host: "a"}},
+	}
+
+	content, err := EncodeWithOpts(element, ".json", EncodeOpts{
+		OnValue: func(leaf Leaf) interface{} {
+			if len(leaf.Fields) > 1 && leaf.Fields[len(leaf.Fields)-1].Name == "Host" {
+				return leaf.Fields[0].Name + "-" + leaf.Encoded.(string)
+			}
+			return leaf.Encoded
+		},
+	})
+	require.NoError(t, err)
+
+	expected := `{
+  "name": "myapp",
+  "debug": false,
+  "ratio": 0,
+  "maxSize": 0,
+  "timeout": "0s",
+  "interval": "0s",
+  "server": {
+    "host": "Server-localhost",
+    "port": 8080
+  },
+  "servers": [
+    {
+      "host": "Servers-a",
+      "port": 0
+    }
+  ]
+}
+`
+	assert.Equal(t, expected, string(content))
+}
+
+func TestEncodeToMap(t *testing.T) {
+	element := &encodedConfig{
+		Name:   "myapp",
+		Labels: map[string]string{"b": "2", "a": "1"},
+	}
+
+	tree, err := EncodeToMap(element, EncodeOpts{})
+	require.NoError(t, err)
+
+	var keys []string
+	tree.Range(func(key string, value interface{}) {
+		keys = append(keys, key)
+	})
+	assert.Equal(t, []string{"name", "debug", "ratio", "maxSize", "timeout", "interval", "sources", "ports", "servers", "labels"}, keys)
+}
+
+func TestKeyName(t *testing.T) {
+	testCases := map[string]string{
+		"Host":        "host",
+		"FTP":         "ftp",
+		"TLS":         "tls",
+		"DisableUTF8": "disableUTF8",
+		"LogJSON":     "logJSON",
+		"URLPath":     "urlPath",
+		"APIKey":      "apiKey",
+		"UID":         "uid",
+	}
+
+	for name, expected := range testCases {
+		assert.Equal(t, expected, KeyName(name))
+	}
+}
@@ -2,11 +2,11 @@
 package file
 
 import (
-	"fmt"
+	"errors"
+	"reflect"
+	"strings"
 
-	"github.com/BurntSushi/toml"
 	"github.com/crazy-max/gonfig/parser"
-	"gopkg.in/yaml.v3"
 )
 
 const defaultRawSliceSeparator = "║"
@@ -17,24 +17,64 @@ const defaultRawSliceSeparator = "║"
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func Decode(filePath string, element interface{}) error {
+	return DecodeWithOpts(filePath, element, DecodeOpts{})
+}
+
+// DecodeOpts holds options used when decoding a configuration file.
+type DecodeOpts struct {
+	// Interpolate expands the environment variable references in the values:
+	// ${VAR}, ${VAR:-default} if VAR is unset or empty, and ${VAR:?error} to fail if VAR is unset or empty.
+	// $$ is an escaped $.
+	Interpolate bool
+	// OnFill is called for each leaf value set into the element,
+	// with its dotted path, its key as written in the file and the file it has been read from.
+	OnFill func(path, key, filename string)
+}
+
+// DecodeWithOpts decodes the given configuration file into the given element using opts.
+func DecodeWithOpts(filePath string, element interface{}, opts DecodeOpts) error {
+	return DecodeFilesWithOpts([]string{filePath}, element, opts)
+}
+
+// DecodeFiles decodes the given configuration files into the given element.
+// The files are merged in order: the values of a file override the ones of the previous files.
+func DecodeFiles(filePaths []string, element interface{}) error {
+	return DecodeFilesWithOpts(filePaths, element, DecodeOpts{})
+}
+
+// DecodeFilesWithOpts decodes the given configuration files into the given element using opts.
+func DecodeFilesWithOpts(filePaths []string, element interface{}, opts DecodeOpts) error {
 	if element == nil {
 		return nil
 	}
 
 	filters := getRootFieldNames(element)
 
-	root, err := decodeFileToNode(filePath, filters...)
+	root, origins, err := decodeFilesToNode(filePaths, reflect.TypeOf(element), filters...)
 	if err != nil {
 		return err
 	}
 
+	if opts.Interpolate {
+		if err = interpolateNode(root, ""); err != nil {
+			return err
+		}
+	}
+
 	metaOpts := parser.MetadataOpts{TagName: parser.TagFile, AllowSliceAsStruct: false}
 	err = parser.AddMetadata(element, root, metaOpts)
 	if err != nil {
 		return err
 	}
 
-	return parser.Fill(element, root, parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator})
+	fillOpts := parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator}
+	if opts.OnFill != nil {
+		fillOpts.OnFill = func(path, key string) {
+			opts.OnFill(path, key, origins[key])
+		}
+	}
+
+	return parser.Fill(element, root, fillOpts)
 }
 
 // DecodeContent decodes the given configuration file content into the given element.
@@ -43,42 +83,47 @@ func Decode(filePath string, element interface{}) error {
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func DecodeContent(content, extension string, element interface{}) error {
-	data := make(map[string]interface{})
-
-	switch extension {
-	case ".toml":
-		_, err := toml.Decode(content, &data)
-		if err != nil {
-			return err
-		}
-
-	case ".yml", ".yaml":
-		var err error
-		err = yaml.Unmarshal([]byte(content), &data)
-		if err != nil {
-			return err
-		}
-
-	default:
-		return fmt.Errorf("unsupported file extension: %s", extension)
-	}
+	return DecodeContentWithOpts(content, extension, element, DecodeOpts{})
+}
 
+// DecodeContentWithOpts decodes the given configuration file content into the given element using opts.
+func DecodeContentWithOpts(content, extension string, element interface{}, opts DecodeOpts) error {
 	filters := getRootFieldNames(element)
 
-	node, err := decodeRawToNode(data, filters...)
+	node, _, err := decodeContentToNode([]byte(content), extension, false, filters...)
+	if errors.Is(err, errNoConfiguration) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	if strings.EqualFold(extension, ".hcl") {
+		wrapBlocks(node, reflect.TypeOf(element))
+	}
+
 	if len(node.Children) == 0 {
 		return nil
 	}
 
+	if opts.Interpolate {
+		if err = interpolateNode(node, ""); err != nil {
+			return err
+		}
+	}
+
 	metaOpts := parser.MetadataOpts{TagName: parser.TagFile, AllowSliceAsStruct: false}
 	err = parser.AddMetadata(element, node, metaOpts)
 	if err != nil {
 		return err
 	}
 
-	return parser.Fill(element, node, parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator})
+	fillOpts := parser.FillerOpts{AllowSliceAsStruct: false, RawSliceSeparator: defaultRawSliceSeparator}
+	if opts.OnFill != nil {
+		fillOpts.OnFill = func(path, key string) {
+			opts.OnFill(path, key, "")
+		}
+	}
+
+	return parser.Fill(element, node, fillOpts)
 }
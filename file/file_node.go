@@ -1,6 +1,7 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,46 +14,177 @@ import (
 )
 
 // decodeFileToNode decodes the configuration in filePath in a tree of untyped nodes.
+// The files listed by the include key are decoded first, and the configuration of filePath is merged over them.
 // If filters is not empty, it skips any configuration element whose name is not among filters.
 func decodeFileToNode(filePath string, filters ...string) (*parser.Node, error) {
+	node, _, err := decodeFilesToNode([]string{filePath}, nil, filters...)
+	return node, err
+}
+
+// decodeFilesToNode decodes the configurations in filePaths and merges them in order in a tree of untyped nodes,
+// rType being the type of the element they are decoded into, if known.
+// It also returns the file each leaf value has been read from, by key.
+func decodeFilesToNode(filePaths []string, rType reflect.Type, filters ...string) (*parser.Node, map[string]string, error) {
+	inc := &includer{rType: rType, filters: filters}
+
+	root := &parser.Node{Name: parser.DefaultRootName}
+	for _, filePath := range filePaths {
+		node, err := inc.decode(filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		mergeNodes(root, node)
+	}
+
+	if len(root.Children) == 0 {
+		return nil, nil, fmt.Errorf("no valid configuration found in file: %s", strings.Join(filePaths, ", "))
+	}
+
+	return root, inc.getOrigins(root, "", nil), nil
+}
+
+// errNoConfiguration is returned when a content is empty.
+var errNoConfiguration = errors.New("no configuration found")
+
+// IsEmpty reports whether the configuration file filePath holds no configuration,
+// e.g. if it is empty or only has comments.
+func IsEmpty(filePath string) (bool, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if !isSupportedExtension(ext) {
+		return false, fmt.Errorf("unsupported file extension: %s", filePath)
+	}
+
 	content, err := os.ReadFile(filepath.Clean(filePath))
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	data := make(map[string]interface{})
+	_, _, err = decodeContentToNode(content, ext, false)
+	if errors.Is(err, errNoConfiguration) {
+		return true, nil
+	}
 
-	switch strings.ToLower(filepath.Ext(filePath)) {
-	case ".toml":
-		err = toml.Unmarshal(content, &data)
+	return false, err
+}
+
+// readFile decodes the configuration in filePath according to its extension in a tree of untyped nodes,
+// and returns the files it includes.
+func readFile(filePath string, filters ...string) (*parser.Node, []string, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if !isSupportedExtension(ext) {
+		return nil, nil, fmt.Errorf("unsupported file extension: %s", filePath)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, includes, err := decodeContentToNode(content, ext, hasIncludeDirective(filters), filters...)
+	if errors.Is(err, errNoConfiguration) {
+		return nil, nil, fmt.Errorf("no configuration found in file: %s", filePath)
+	}
+
+	return node, includes, err
+}
+
+// decodeContentToNode decodes content according to the file extension in a tree of untyped nodes.
+// If includes is true, it removes the include key and returns the files it lists.
+func decodeContentToNode(content []byte, extension string, includes bool, filters ...string) (*parser.Node, []string, error) {
+	if strings.EqualFold(extension, ".properties") {
+		labels, err := decodeProperties(content)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		if len(labels) == 0 {
+			return nil, nil, errNoConfiguration
 		}
 
-	case ".yml", ".yaml", ".json":
-		err = yaml.Unmarshal(content, data)
+		var paths []string
+		if includes {
+			paths = getPropertiesIncludes(labels)
+		}
+
+		node, err := propertiesToNode(labels, filters...)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-	default:
-		return nil, fmt.Errorf("unsupported file extension: %s", filePath)
+		return node, paths, nil
+	}
+
+	data, err := unmarshal(content, extension)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if len(data) == 0 {
-		return nil, fmt.Errorf("no configuration found in file: %s", filePath)
+		return nil, nil, errNoConfiguration
+	}
+
+	var paths []string
+	if includes {
+		if paths, err = getIncludes(data); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	node, err := decodeRawToNode(data, filters...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	return node, paths, nil
+}
+
+// SupportedExtensions returns the extensions of the supported configuration files, without the leading dot.
+func SupportedExtensions() []string {
+	return []string{"toml", "yaml", "yml", "json", "jsonc", "json5", "hcl", "ini", "properties"}
+}
+
+func isSupportedExtension(extension string) bool {
+	for _, ext := range SupportedExtensions() {
+		if strings.EqualFold(extension, "."+ext) {
+			return true
+		}
 	}
+	return false
+}
+
+// unmarshal decodes the raw configuration in content according to the file extension.
+func unmarshal(content []byte, extension string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	switch strings.ToLower(extension) {
+	case ".toml":
+		if err := toml.Unmarshal(content, &data); err != nil {
+			return nil, err
+		}
 
-	if len(node.Children) == 0 {
-		return nil, fmt.Errorf("no valid configuration found in file: %s", filePath)
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(content, data); err != nil {
+			return nil, err
+		}
+
+	case ".json":
+		return decodeJSON(content)
+
+	case ".jsonc", ".json5":
+		return decodeJSONC(content)
+
+	case ".hcl":
+		return decodeHCL(content)
+
+	case ".ini":
+		return decodeINI(content)
+
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", extension)
 	}
 
-	return node, nil
+	return data, nil
 }
 
 func getRootFieldNames(element interface{}) []string {
@@ -1,6 +1,8 @@
 package file
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/crazy-max/gonfig/parser"
@@ -924,3 +926,39 @@ func Test_decodeFileToNode_Json(t *testing.T) {
 
 	assert.Equal(t, expected, node)
 }
+
+func TestIsEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		filename string
+		content  string
+		expected bool
+	}{
+		{filename: "empty.yml", content: "", expected: true},
+		{filename: "comments.yml", content: "# foo: bar\n", expected: true},
+		{filename: "comments.toml", content: "# [foo]\n", expected: true},
+		{filename: "comments.properties", content: "# foo=bar\n", expected: true},
+		{filename: "empty.json", content: "", expected: true},
+		{filename: "blank.json", content: "\n  \n", expected: true},
+		{filename: "object.json", content: "{}\n", expected: true},
+		{filename: "comments.jsonc", content: "// {\"foo\": \"bar\"}\n/* comment */\n", expected: true},
+		{filename: "comments.json5", content: "  // foo: 'bar'\n", expected: true},
+		{filename: "config.json", content: "{\"foo\": \"bar\"}\n", expected: false},
+		{filename: "config.yml", content: "foo: bar\n", expected: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.filename, func(t *testing.T) {
+			filePath := filepath.Join(dir, test.filename)
+			require.NoError(t, os.WriteFile(filePath, []byte(test.content), 0o600))
+
+			empty, err := IsEmpty(filePath)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, empty)
+		})
+	}
+
+	_, err := IsEmpty(filepath.Join(dir, "config.md"))
+	require.Error(t, err)
+}
@@ -373,3 +373,198 @@ bar:
 	}
 	assert.Equal(t, expected, element)
 }
+
+func TestDecode_JSON(t *testing.T) {
+	f, err := os.CreateTemp("", "gonfig-*.json")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write([]byte(`{
+  "foo": "bar",
+  "fii": "bir",
+  "yi": {}
+}`))
+	require.NoError(t, err)
+
+	element := &Yo{
+		Fuu: "test",
+	}
+
+	err = Decode(f.Name(), element)
+	require.NoError(t, err)
+
+	expected := &Yo{
+		Foo: "bar",
+		Fii: "bir",
+		Fuu: "test",
+		Yi: &Yi{
+			Foo: "foo",
+			Fii: "fii",
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_JSON_errors(t *testing.T) {
+	f, err := os.CreateTemp("", "gonfig-*.json")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write([]byte("foo: bar\nfii: bir\n"))
+	require.NoError(t, err)
+
+	err = Decode(f.Name(), &Yo{})
+	require.EqualError(t, err, "json: line 1, column 2: invalid character 'o' in literal false (expecting 'a')")
+}
+
+func TestDecodeContent_JSON(t *testing.T) {
+	content := `{
+  "foo": "bar",
+  "fii": "bir",
+  "yi": {}
+}`
+
+	element := &Yo{
+		Fuu: "test",
+	}
+
+	err := DecodeContent(content, ".json", element)
+	require.NoError(t, err)
+
+	expected := &Yo{
+		Foo: "bar",
+		Fii: "bir",
+		Fuu: "test",
+		Yi: &Yi{
+			Foo: "foo",
+			Fii: "fii",
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecodeContent_empty(t *testing.T) {
+	for _, extension := range []string{".json", ".jsonc", ".json5", ".yml", ".toml"} {
+		t.Run(extension, func(t *testing.T) {
+			element := &Yo{Fuu: "test"}
+
+			err := DecodeContent("\n", extension, element)
+			require.NoError(t, err)
+
+			assert.Equal(t, &Yo{Fuu: "test"}, element)
+		})
+	}
+}
+
+func TestDecodeContent_JSON_rawValue(t *testing.T) {
+	content := `{
+  "testData": {
+    "big": 9007199254740993,
+    "list": [1, 2]
+  }
+}`
+
+	var element FooRaw
+	err := DecodeContent(content, ".json", &element)
+	require.NoError(t, err)
+
+	expected := FooRaw{
+		TestData: map[string]interface{}{
+			"big":  "9007199254740993",
+			"list": []interface{}{int64(1), int64(2)},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecodeContent_JSON_largeNumbers(t *testing.T) {
+	content := `{"int": 9007199254740993, "uint": 18446744073709551615}`
+
+	element := &struct {
+		Int  int64
+		Uint uint64
+	}{}
+
+	err := DecodeContent(content, ".json", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(9007199254740993), element.Int)
+	assert.Equal(t, uint64(18446744073709551615), element.Uint)
+}
+
+func TestDecodeContent_JSON_duplicateKey(t *testing.T) {
+	content := `{"foo": "bar", "foo": "baz"}`
+
+	err := DecodeContent(content, ".json", &Yo{})
+	require.EqualError(t, err, `json: line 1, column 16: duplicate key "foo"`)
+}
+
+func TestDecodeContent_YAML_interpolate(t *testing.T) {
+	t.Setenv("GONFIG_TEST_FOO", "bar")
+
+	content := `
+foo: ${GONFIG_TEST_FOO}
+fii: ${GONFIG_TEST_UNSET:-default}
+fuu: $${GONFIG_TEST_FOO}
+`
+
+	element := &Yo{}
+	err := DecodeContentWithOpts(content, ".yml", element, DecodeOpts{Interpolate: true})
+	require.NoError(t, err)
+
+	expected := &Yo{
+		Foo: "bar",
+		Fii: "default",
+		Fuu: "${GONFIG_TEST_FOO}",
+	}
+	assert.Equal(t, expected, element)
+
+	// disabled by default
+	element = &Yo{}
+	err = DecodeContent(content, ".yml", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, "${GONFIG_TEST_FOO}", element.Foo)
+	assert.Equal(t, "$${GONFIG_TEST_FOO}", element.Fuu)
+}
+
+func TestDecodeContent_YAML_interpolateRawValue(t *testing.T) {
+	t.Setenv("GONFIG_TEST_FOO", "bar")
+
+	content := `
+testData:
+  foo: ${GONFIG_TEST_FOO}
+  list:
+    - ${GONFIG_TEST_FOO}
+    - baz
+  sub:
+    host: ${GONFIG_TEST_UNSET:-localhost}
+`
+
+	var element FooRaw
+	err := DecodeContentWithOpts(content, ".yml", &element, DecodeOpts{Interpolate: true})
+	require.NoError(t, err)
+
+	expected := FooRaw{
+		TestData: map[string]interface{}{
+			"foo":  "bar",
+			"list": []interface{}{"bar", "baz"},
+			"sub":  map[string]interface{}{"host": "localhost"},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecodeContent_YAML_interpolateError(t *testing.T) {
+	content := `
+yi:
+  foo: ${GONFIG_TEST_UNSET:?must be set}
+`
+
+	err := DecodeContentWithOpts(content, ".yml", &Yo{}, DecodeOpts{Interpolate: true})
+	require.EqualError(t, err, "failed to interpolate yi.foo: GONFIG_TEST_UNSET: must be set")
+}
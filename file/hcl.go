@@ -0,0 +1,646 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// decodeHCL decodes the body of an HCL configuration.
+// Attributes are mapped to values, blocks to nested maps, labeled blocks to maps keyed by label
+// and repeated unlabeled blocks to lists of maps (see wrapBlocks for the single ones).
+// Only literal expressions are supported: strings (kept as is, templates included), heredocs,
+// numbers, booleans, null, tuples and objects.
+func decodeHCL(content []byte) (map[string]interface{}, error) {
+	p := &hclParser{src: string(content), line: 1, column: 1}
+
+	data, err := p.body(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// wrapBlocks turns the single unlabeled blocks of node that match a slice of structs of rType
+// into one-item lists, as repeated blocks are, e.g. a single backends { ... } block into a []Backend field.
+func wrapBlocks(node *parser.Node, rType reflect.Type) {
+	for rType != nil && rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+	if rType == nil {
+		return
+	}
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		for _, child := range node.Children {
+			field, ok := findField(rType, child.Name)
+			if !ok {
+				continue
+			}
+
+			fType := field.Type
+			if fType.Kind() == reflect.Slice && isStruct(fType.Elem()) {
+				if isBranch(child) {
+					child.Children = []*parser.Node{{Name: "[0]", Children: child.Children}}
+				}
+				for _, item := range child.Children {
+					wrapBlocks(item, fType.Elem())
+				}
+				continue
+			}
+
+			wrapBlocks(child, fType)
+		}
+
+	case reflect.Map:
+		for _, child := range node.Children {
+			wrapBlocks(child, rType.Elem())
+		}
+	}
+}
+
+// findField returns the field of the struct type rType matching the key name, including the embedded fields.
+func findField(rType reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if !parser.IsExported(field) {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if f, ok := findField(field.Type, name); ok {
+				return f, true
+			}
+			continue
+		}
+
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func isStruct(rType reflect.Type) bool {
+	if rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+	}
+	return rType.Kind() == reflect.Struct && rType != reflect.TypeOf(time.Time{})
+}
+
+type hclParser struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+// hclBody holds the content of a body and the kind of its items.
+type hclBody struct {
+	data map[string]interface{}
+	// kinds holds "an attribute", "a block" or "a labeled block" by name.
+	kinds map[string]string
+}
+
+// body parses the items of a body until end (0 for the end of the content).
+func (p *hclParser) body(end byte) (map[string]interface{}, error) {
+	b := hclBody{data: make(map[string]interface{}), kinds: make(map[string]string)}
+
+	for {
+		p.skip(true)
+
+		if p.eof() {
+			if end != 0 {
+				return nil, p.errorf("missing closing %q", end)
+			}
+			return b.data, nil
+		}
+
+		if p.peek() == end {
+			return b.data, nil
+		}
+
+		line, column := p.line, p.column
+
+		name := p.ident()
+		if name == "" {
+			return nil, p.errorf("expected an attribute or a block, got %s", p.describe())
+		}
+
+		p.skip(false)
+
+		if !p.eof() && p.peek() == '=' {
+			p.next()
+
+			value, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := b.kinds[name]; ok {
+				return nil, fmt.Errorf("hcl: line %d, column %d: duplicate %q", line, column, name)
+			}
+			b.kinds[name] = "an attribute"
+			b.data[name] = value
+		} else {
+			labels, err := p.labels()
+			if err != nil {
+				return nil, err
+			}
+
+			p.next() // {
+
+			content, err := p.body('}')
+			if err != nil {
+				return nil, err
+			}
+
+			p.next() // }
+
+			if err = b.addBlock(name, labels, content); err != nil {
+				return nil, fmt.Errorf("hcl: line %d, column %d: %w", line, column, err)
+			}
+		}
+
+		if err := p.endOfItem(end); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (b hclBody) addBlock(name string, labels []string, content map[string]interface{}) error {
+	kind := "a block"
+	if len(labels) > 0 {
+		kind = "a labeled block"
+	}
+
+	if existing, ok := b.kinds[name]; ok && existing != kind {
+		return fmt.Errorf("%q is already defined as %s", name, existing)
+	}
+	b.kinds[name] = kind
+
+	if len(labels) == 0 {
+		switch v := b.data[name].(type) {
+		case nil:
+			b.data[name] = content
+		case map[string]interface{}:
+			b.data[name] = []interface{}{v, content}
+		case []interface{}:
+			b.data[name] = append(v, content)
+		}
+		return nil
+	}
+
+	m, _ := b.data[name].(map[string]interface{})
+	if m == nil {
+		m = make(map[string]interface{})
+		b.data[name] = m
+	}
+
+	for _, label := range labels[:len(labels)-1] {
+		sub, _ := m[label].(map[string]interface{})
+		if sub == nil {
+			sub = make(map[string]interface{})
+			m[label] = sub
+		}
+		m = sub
+	}
+
+	last := labels[len(labels)-1]
+	if _, ok := m[last]; ok {
+		return fmt.Errorf("duplicate block %s %q", name, strings.Join(labels, `" "`))
+	}
+	m[last] = content
+
+	return nil
+}
+
+// labels parses the labels of a block up to its opening brace.
+func (p *hclParser) labels() ([]string, error) {
+	var labels []string
+
+	for {
+		p.skip(false)
+
+		switch {
+		case p.eof():
+			return nil, p.errorf("unexpected end of content, expected a block")
+		case p.peek() == '{':
+			return labels, nil
+		case p.peek() == '"':
+			label, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			labels = append(labels, label)
+		default:
+			label := p.ident()
+			if label == "" {
+				return nil, p.errorf("expected \"=\", a block label or \"{\", got %s", p.describe())
+			}
+			labels = append(labels, label)
+		}
+	}
+}
+
+// endOfItem checks that an item is followed by a newline, a comment or the end of its body.
+func (p *hclParser) endOfItem(end byte) error {
+	p.skip(false)
+
+	if p.eof() || p.peek() == '\n' || p.peek() == end {
+		return nil
+	}
+
+	return p.errorf("expected a newline after the item, got %s", p.describe())
+}
+
+func (p *hclParser) expr() (interface{}, error) {
+	p.skip(false)
+
+	if p.eof() {
+		return nil, p.errorf("unexpected end of content, expected a value")
+	}
+
+	switch c := p.peek(); {
+	case c == '"':
+		return p.quoted()
+	case strings.HasPrefix(p.src[p.pos:], "<<"):
+		return p.heredoc()
+	case c == '[':
+		return p.tuple()
+	case c == '{':
+		return p.object()
+	case c == '-' || c >= '0' && c <= '9':
+		return p.number()
+	default:
+		line, column := p.line, p.column
+
+		switch ident := p.ident(); ident {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		case "":
+			return nil, p.errorf("expected a value, got %s", p.describe())
+		default:
+			return nil, fmt.Errorf("hcl: line %d, column %d: unsupported expression %q, only literal values are supported", line, column, ident)
+		}
+	}
+}
+
+func (p *hclParser) tuple() ([]interface{}, error) {
+	p.next() // [
+
+	list := make([]interface{}, 0)
+	for {
+		p.skip(true)
+
+		if p.eof() {
+			return nil, p.errorf("missing closing %q", ']')
+		}
+
+		if p.peek() == ']' {
+			p.next()
+			return list, nil
+		}
+
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+
+		p.skip(true)
+		switch {
+		case p.eof():
+			return nil, p.errorf("missing closing %q", ']')
+		case p.peek() == ',':
+			p.next()
+		case p.peek() != ']':
+			return nil, p.errorf("expected \",\" or \"]\", got %s", p.describe())
+		}
+	}
+}
+
+func (p *hclParser) object() (map[string]interface{}, error) {
+	p.next() // {
+
+	data := make(map[string]interface{})
+	for {
+		p.skip(true)
+
+		if p.eof() {
+			return nil, p.errorf("missing closing %q", '}')
+		}
+
+		if p.peek() == '}' {
+			p.next()
+			return data, nil
+		}
+
+		line, column := p.line, p.column
+
+		var key string
+		if p.peek() == '"' {
+			var err error
+			if key, err = p.quoted(); err != nil {
+				return nil, err
+			}
+		} else if key = p.ident(); key == "" {
+			return nil, p.errorf("expected an object key, got %s", p.describe())
+		}
+
+		p.skip(false)
+		if p.eof() || (p.peek() != '=' && p.peek() != ':') {
+			return nil, p.errorf("expected \"=\" or \":\" after the object key, got %s", p.describe())
+		}
+		p.next()
+
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := data[key]; ok {
+			return nil, fmt.Errorf("hcl: line %d, column %d: duplicate %q", line, column, key)
+		}
+		data[key] = value
+
+		p.skip(false)
+		switch {
+		case p.eof():
+			return nil, p.errorf("missing closing %q", '}')
+		case p.peek() == ',' || p.peek() == '\n':
+			p.next()
+		case p.peek() != '}':
+			return nil, p.errorf("expected \",\", a newline or \"}\", got %s", p.describe())
+		}
+	}
+}
+
+func (p *hclParser) number() (interface{}, error) {
+	start := p.pos
+
+	if p.peek() == '-' {
+		p.next()
+	}
+
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.next()
+			n++
+		}
+		return n
+	}
+
+	if digits() == 0 {
+		return nil, p.errorf("invalid number")
+	}
+
+	if !p.eof() && p.peek() == '.' {
+		p.next()
+		if digits() == 0 {
+			return nil, p.errorf("invalid number")
+		}
+	}
+
+	if !p.eof() && (p.peek() == 'e' || p.peek() == 'E') {
+		p.next()
+		if !p.eof() && (p.peek() == '+' || p.peek() == '-') {
+			p.next()
+		}
+		if digits() == 0 {
+			return nil, p.errorf("invalid number")
+		}
+	}
+
+	return number(json.Number(p.src[start:p.pos])), nil
+}
+
+// quoted parses a quoted string. Templates such as ${var} are kept as is.
+func (p *hclParser) quoted() (string, error) {
+	line, column := p.line, p.column
+	p.next() // "
+
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", fmt.Errorf("hcl: line %d, column %d: unterminated string", line, column)
+		}
+
+		c := p.next()
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", fmt.Errorf("hcl: line %d, column %d: unterminated string", line, column)
+			}
+
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(e)
+			case 'u', 'U':
+				size := 4
+				if e == 'U' {
+					size = 8
+				}
+				if p.pos+size > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				for i := 0; i < size; i++ {
+					p.next()
+				}
+				b.WriteRune(rune(r))
+			default:
+				return "", p.errorf("invalid escape sequence \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// heredoc parses a <<MARKER or <<-MARKER heredoc. The indented form strips the common leading spaces.
+func (p *hclParser) heredoc() (string, error) {
+	line, column := p.line, p.column
+	p.next()
+	p.next()
+
+	indented := false
+	if !p.eof() && p.peek() == '-' {
+		indented = true
+		p.next()
+	}
+
+	marker := p.ident()
+	if marker == "" {
+		return "", p.errorf("expected a heredoc marker")
+	}
+
+	p.skip(false)
+	if p.eof() || p.peek() != '\n' {
+		return "", p.errorf("expected a newline after the heredoc marker")
+	}
+	p.next()
+
+	var lines []string
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("hcl: line %d, column %d: unterminated heredoc, missing %s", line, column, marker)
+		}
+
+		start := p.pos
+		for !p.eof() && p.peek() != '\n' {
+			p.next()
+		}
+		text := p.src[start:p.pos]
+
+		if strings.TrimSpace(text) == marker {
+			break
+		}
+
+		lines = append(lines, text)
+		if !p.eof() {
+			p.next()
+		}
+	}
+
+	if indented {
+		lines = unindent(lines)
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func unindent(lines []string) []string {
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			result[i] = l[indent:]
+		} else {
+			result[i] = strings.TrimLeft(l, " \t")
+		}
+	}
+
+	return result
+}
+
+func (p *hclParser) ident() string {
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !(unicode.IsLetter(r) || r == '_' || p.pos > start && (unicode.IsDigit(r) || r == '-')) {
+			break
+		}
+		p.pos += size
+		p.column++
+	}
+	return p.src[start:p.pos]
+}
+
+// skip skips the spaces and the comments, and the newlines if newlines is true.
+func (p *hclParser) skip(newlines bool) {
+	for !p.eof() {
+		rest := p.src[p.pos:]
+
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			p.next()
+		case rest[0] == '\n' && newlines:
+			p.next()
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			n := len(rest)
+			if end >= 0 {
+				n = end + 4
+			}
+			for i := 0; i < n; i++ {
+				p.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *hclParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *hclParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *hclParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+
+	if c == '\n' {
+		p.line++
+		p.column = 1
+	} else if utf8.RuneStart(c) {
+		p.column++
+	}
+
+	return c
+}
+
+func (p *hclParser) describe() string {
+	if p.eof() {
+		return "end of content"
+	}
+	if p.peek() == '\n' {
+		return "newline"
+	}
+
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *hclParser) errorf(format string, a ...interface{}) error {
+	return fmt.Errorf("hcl: line %d, column %d: %s", p.line, p.column, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,319 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeHCL(t *testing.T) {
+	content := `
+# comment
+name = "myapp" // comment
+port = 8080
+ratio = -1.5
+enabled = true
+nothing = null
+template = "http://${HOST}:$${PORT}/\"path\"\né"
+tags = ["a", "b",
+  "c",
+]
+meta = { foo = "bar", "baz": 1
+  qux = [] }
+
+/* multi-line
+   comment */
+server {
+  host = "localhost"
+
+  tls {}
+}
+
+notif "mail" {
+  host = "smtp.example.com"
+}
+
+notif webhook {
+  endpoint = "http://webhook"
+}
+
+backend {
+  url = "http://a"
+}
+
+backend {
+  url = "http://b"
+}
+
+route "api" "v1" {
+  path = "/api/v1"
+}
+
+route "api" "v2" {
+  path = "/api/v2"
+}
+
+script = <<EOT
+echo foo
+  echo bar
+EOT
+
+indented = <<-EOT
+    foo
+      bar
+    EOT
+`
+
+	data, err := decodeHCL([]byte(content))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":     "myapp",
+		"port":     8080,
+		"ratio":    -1.5,
+		"enabled":  true,
+		"nothing":  nil,
+		"template": "http://${HOST}:$${PORT}/\"path\"\né",
+		"tags":     []interface{}{"a", "b", "c"},
+		"meta":     map[string]interface{}{"foo": "bar", "baz": 1, "qux": []interface{}{}},
+		"server": map[string]interface{}{
+			"host": "localhost",
+			"tls":  map[string]interface{}{},
+		},
+		"notif": map[string]interface{}{
+			"mail":    map[string]interface{}{"host": "smtp.example.com"},
+			"webhook": map[string]interface{}{"endpoint": "http://webhook"},
+		},
+		"backend": []interface{}{
+			map[string]interface{}{"url": "http://a"},
+			map[string]interface{}{"url": "http://b"},
+		},
+		"route": map[string]interface{}{
+			"api": map[string]interface{}{
+				"v1": map[string]interface{}{"path": "/api/v1"},
+				"v2": map[string]interface{}{"path": "/api/v2"},
+			},
+		},
+		"script":   "echo foo\n  echo bar\n",
+		"indented": "foo\n  bar\n",
+	}
+	assert.Equal(t, expected, data)
+}
+
+func Test_decodeHCL_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "duplicate attribute",
+			content:  "foo = 1\nfoo = 2\n",
+			expected: `hcl: line 2, column 1: duplicate "foo"`,
+		},
+		{
+			desc:     "duplicate labeled block",
+			content:  "notif \"mail\" {}\nnotif \"mail\" {}\n",
+			expected: `hcl: line 2, column 1: duplicate block notif "mail"`,
+		},
+		{
+			desc:     "attribute and block",
+			content:  "server = 1\nserver {}\n",
+			expected: `hcl: line 2, column 1: "server" is already defined as an attribute`,
+		},
+		{
+			desc:     "unclosed block",
+			content:  "server {\n  host = \"localhost\"\n",
+			expected: `hcl: line 3, column 1: missing closing '}'`,
+		},
+		{
+			desc:     "unterminated string",
+			content:  "foo = \"bar\n",
+			expected: "hcl: line 1, column 7: unterminated string",
+		},
+		{
+			desc:     "variable",
+			content:  "foo = var.bar\n",
+			expected: `hcl: line 1, column 7: unsupported expression "var", only literal values are supported`,
+		},
+		{
+			desc:     "two attributes on a line",
+			content:  "foo = 1 bar = 2\n",
+			expected: `hcl: line 1, column 9: expected a newline after the item, got 'b'`,
+		},
+		{
+			desc:     "missing value",
+			content:  "foo =\n",
+			expected: `hcl: line 1, column 6: expected a value, got newline`,
+		},
+		{
+			desc:     "missing equal",
+			content:  "foo\n",
+			expected: `hcl: line 1, column 4: expected "=", a block label or "{", got newline`,
+		},
+		{
+			desc:     "unterminated heredoc",
+			content:  "foo = <<EOT\nbar\n",
+			expected: "hcl: line 1, column 7: unterminated heredoc, missing EOT",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := decodeHCL([]byte(test.content))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeContent_HCL(t *testing.T) {
+	type notif struct {
+		Host string
+		Port int
+	}
+
+	type config struct {
+		Name    string
+		Ignored string `file:"-"`
+		Yi      *Yi    `file:"allowEmpty"`
+		Notif   map[string]notif
+		Sources []string
+	}
+
+	content := `
+name    = "myapp"
+ignored = "foo"
+sources = ["/", "/tmp"]
+
+yi {}
+
+notif "mail" {
+  host = "smtp.example.com"
+  port = 587
+}
+
+notif "slack" {
+  host = "hooks.slack.com"
+}
+`
+
+	element := &config{}
+	err := DecodeContent(content, ".hcl", element)
+	require.NoError(t, err)
+
+	expected := &config{
+		Name: "myapp",
+		Yi: &Yi{
+			Foo: "foo",
+			Fii: "fii",
+		},
+		Notif: map[string]notif{
+			"mail":  {Host: "smtp.example.com", Port: 587},
+			"slack": {Host: "hooks.slack.com"},
+		},
+		Sources: []string{"/", "/tmp"},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_HCL(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.hcl": "include = [\"db.hcl\"]\nname = \"myapp\"\n",
+		"db.hcl":     "db {\n  host = \"db.local\"\n  port = 5432\n}\n",
+	})
+
+	element := &includedConfig{}
+	err := Decode(filepath.Join(dir, "config.hcl"), element)
+	require.NoError(t, err)
+
+	expected := &includedConfig{
+		Name: "myapp",
+		DB:   &includedDB{Host: "db.local", Port: 5432},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_HCL_blockLists(t *testing.T) {
+	type target struct {
+		URL    string
+		Weight int
+	}
+
+	type backend struct {
+		URL     string
+		Targets []*target
+	}
+
+	type config struct {
+		Backends []backend
+		Services map[string]struct {
+			Backends []backend
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		content  string
+		expected *config
+	}{
+		{
+			desc:    "single block",
+			content: "backends {\n  url = \"u\"\n}\n",
+			expected: &config{
+				Backends: []backend{{URL: "u"}},
+			},
+		},
+		{
+			desc:    "repeated blocks",
+			content: "backends {\n  url = \"a\"\n}\n\nbackends {\n  url = \"b\"\n}\n",
+			expected: &config{
+				Backends: []backend{{URL: "a"}, {URL: "b"}},
+			},
+		},
+		{
+			desc: "nested single blocks",
+			content: `
+backends {
+  url = "a"
+
+  targets {
+    url    = "t"
+    weight = 2
+  }
+}
+
+services "web" {
+  backends {
+    url = "w"
+  }
+}
+`,
+			expected: &config{
+				Backends: []backend{{URL: "a", Targets: []*target{{URL: "t", Weight: 2}}}},
+				Services: map[string]struct{ Backends []backend }{
+					"web": {Backends: []backend{{URL: "w"}}},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			element := &config{}
+			err := DecodeContent(test.content, ".hcl", element)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, element)
+
+			filePath := filepath.Join(t.TempDir(), "config.hcl")
+			require.NoError(t, os.WriteFile(filePath, []byte(test.content), 0o600))
+
+			element = &config{}
+			err = Decode(filePath, element)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, element)
+		})
+	}
+}
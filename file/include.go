@@ -0,0 +1,210 @@
+package file
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// IncludeKey is the top-level key listing the files to include in a configuration file.
+// Its value is a path or a list of paths (comma-separated in .properties files),
+// possibly glob patterns, relative to the including file.
+// It is decoded as a regular key by DecodeContent, and when the element has a root field with that name.
+const IncludeKey = "include"
+
+// includer decodes a configuration file and the files it includes.
+type includer struct {
+	// rType is the type of the element the files are decoded into, if known.
+	rType   reflect.Type
+	filters []string
+	chain   []string
+	// origins holds the file each leaf node has been read from.
+	origins map[*parser.Node]string
+}
+
+// decode decodes filePath and merges it over the files it includes, in order.
+func (i *includer) decode(filePath string) (*parser.Node, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range i.chain {
+		if p == absPath {
+			return nil, fmt.Errorf("include cycle detected: %s", i.chainString(absPath))
+		}
+	}
+
+	i.chain = append(i.chain, absPath)
+	defer func() { i.chain = i.chain[:len(i.chain)-1] }()
+
+	node, includes, err := readFile(filePath, i.filters...)
+	if err != nil {
+		return nil, i.wrap(err)
+	}
+
+	if strings.EqualFold(filepath.Ext(filePath), ".hcl") {
+		wrapBlocks(node, i.rType)
+	}
+
+	root := &parser.Node{Name: parser.DefaultRootName}
+
+	for _, pattern := range includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(absPath), pattern)
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, i.wrap(fmt.Errorf("invalid include pattern %q: %w", pattern, err))
+		}
+
+		if len(matches) == 0 && !hasMeta(pattern) {
+			// reports the missing file
+			matches = []string{pattern}
+		}
+
+		for _, match := range matches {
+			included, err := i.decode(match)
+			if err != nil {
+				return nil, err
+			}
+
+			mergeNodes(root, included)
+		}
+	}
+
+	i.setOrigin(node, absPath)
+	mergeNodes(root, node)
+
+	return root, nil
+}
+
+func (i *includer) setOrigin(node *parser.Node, filePath string) {
+	if i.origins == nil {
+		i.origins = make(map[*parser.Node]string)
+	}
+
+	for _, child := range node.Children {
+		if len(child.Children) == 0 {
+			i.origins[child] = filePath
+			continue
+		}
+		i.setOrigin(child, filePath)
+	}
+}
+
+// getOrigins returns the file each leaf of node has been read from, by key (node names joined as in parser.FillerOpts.OnFill).
+func (i *includer) getOrigins(node *parser.Node, key string, result map[string]string) map[string]string {
+	if result == nil {
+		result = make(map[string]string)
+	}
+
+	for _, child := range node.Children {
+		childKey := child.Name
+		if key != "" && strings.HasPrefix(child.Name, "[") {
+			childKey = key + child.Name
+		} else if key != "" {
+			childKey = key + "." + child.Name
+		}
+
+		if len(child.Children) == 0 {
+			result[childKey] = i.origins[child]
+			continue
+		}
+		i.getOrigins(child, childKey, result)
+	}
+
+	return result
+}
+
+// wrap adds the include chain to err if the current file is included by another one.
+func (i *includer) wrap(err error) error {
+	if len(i.chain) < 2 {
+		return err
+	}
+
+	return fmt.Errorf("%w (include chain: %s)", err, i.chainString())
+}
+
+func (i *includer) chainString(extra ...string) string {
+	return strings.Join(append(append([]string{}, i.chain...), extra...), " -> ")
+}
+
+// hasIncludeDirective reports whether the include key is a directive,
+// i.e. no root field of the element, among filters, has that name.
+func hasIncludeDirective(filters []string) bool {
+	for _, name := range filters {
+		if strings.EqualFold(name, IncludeKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// getIncludes removes the include key from data and returns its paths.
+func getIncludes(data map[string]interface{}) ([]string, error) {
+	var value interface{}
+	for key, v := range data {
+		if strings.EqualFold(key, IncludeKey) {
+			value = v
+			delete(data, key)
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		var includes []string
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s value: %v", IncludeKey, item)
+			}
+			includes = append(includes, s)
+		}
+		return includes, nil
+	default:
+		return nil, fmt.Errorf("invalid %s value: %v", IncludeKey, value)
+	}
+}
+
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+// mergeNodes deep merges the children of src into dst, the values of src taking precedence.
+// Leaf values and slices are replaced, not merged.
+func mergeNodes(dst, src *parser.Node) {
+	for _, child := range src.Children {
+		var existing *parser.Node
+		idx := -1
+		for i, c := range dst.Children {
+			if strings.EqualFold(c.Name, child.Name) {
+				existing = c
+				idx = i
+				break
+			}
+		}
+
+		switch {
+		case existing == nil:
+			dst.Children = append(dst.Children, child)
+		case isBranch(existing) && isBranch(child):
+			mergeNodes(existing, child)
+		default:
+			dst.Children[idx] = child
+		}
+	}
+}
+
+// isBranch reports whether node is a map of values (not a leaf nor a slice).
+func isBranch(node *parser.Node) bool {
+	return len(node.Children) > 0 && !strings.HasPrefix(node.Children[0].Name, "[")
+}
@@ -0,0 +1,271 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type includedConfig struct {
+	Name  string
+	DB    *includedDB
+	Notif map[string]*includedNotif
+	Tags  []string
+}
+
+type includedDB struct {
+	Host string
+	Port int
+}
+
+type includedNotif struct {
+	Endpoint string
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func TestDecode_include(t *testing.T) {
+	expected := &includedConfig{
+		Name: "myapp",
+		DB:   &includedDB{Host: "db.local", Port: 5432},
+		Notif: map[string]*includedNotif{
+			"mail":  {Endpoint: "smtp://mail"},
+			"slack": {Endpoint: "https://slack"},
+		},
+		Tags: []string{"c"},
+	}
+
+	testCases := []struct {
+		desc     string
+		files    map[string]string
+		confFile string
+	}{
+		{
+			desc: "yaml",
+			files: map[string]string{
+				"config.yml": `
+include:
+  - ./db.yml
+  - ./notif/*.yml
+name: myapp
+db:
+  port: 5432
+tags: [c]
+`,
+				"db.yml": `
+db:
+  host: db.local
+  port: 3306
+tags: [a, b]
+`,
+				"notif/mail.yml":  "notif:\n  mail:\n    endpoint: smtp://mail\n",
+				"notif/slack.yml": "notif:\n  slack:\n    endpoint: https://slack\n",
+			},
+			confFile: "config.yml",
+		},
+		{
+			desc: "toml",
+			files: map[string]string{
+				"config.toml": `
+include = ["db.toml", "notif/*.toml"]
+name = "myapp"
+tags = ["c"]
+
+[db]
+  port = 5432
+`,
+				"db.toml": `
+tags = ["a", "b"]
+
+[db]
+  host = "db.local"
+  port = 3306
+`,
+				"notif/mail.toml":  "[notif.mail]\n  endpoint = \"smtp://mail\"\n",
+				"notif/slack.toml": "[notif.slack]\n  endpoint = \"https://slack\"\n",
+			},
+			confFile: "config.toml",
+		},
+		{
+			desc: "json and nested includes",
+			files: map[string]string{
+				"config.json": `{
+  "include": "conf.d/base.json",
+  "name": "myapp",
+  "db": {"port": 5432},
+  "tags": ["c"]
+}`,
+				"conf.d/base.json":       `{"include": ["db.yml", "notif.toml", "notif/*.yml"], "name": "base"}`,
+				"conf.d/db.yml":          "db:\n  host: db.local\n  port: 3306\n",
+				"conf.d/notif.toml":      "[notif.mail]\n  endpoint = \"smtp://mail\"\n",
+				"conf.d/notif/slack.yml": "notif:\n  slack:\n    endpoint: https://slack\n",
+			},
+			confFile: "config.json",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			dir := writeFiles(t, test.files)
+
+			element := &includedConfig{}
+			err := Decode(filepath.Join(dir, test.confFile), element)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, element)
+		})
+	}
+}
+
+func TestDecode_include_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			desc: "cycle",
+			files: map[string]string{
+				"config.yml": "include: a.yml\nname: foo\n",
+				"a.yml":      "include: b.yml\nname: a\n",
+				"b.yml":      "include: a.yml\nname: b\n",
+			},
+			expected: "include cycle detected: {dir}/config.yml -> {dir}/a.yml -> {dir}/b.yml -> {dir}/a.yml",
+		},
+		{
+			desc: "self include",
+			files: map[string]string{
+				"config.yml": "include: config.yml\nname: foo\n",
+			},
+			expected: "include cycle detected: {dir}/config.yml -> {dir}/config.yml",
+		},
+		{
+			desc: "missing file",
+			files: map[string]string{
+				"config.yml": "include: a.yml\nname: foo\n",
+				"a.yml":      "include: [b.yml]\nname: a\n",
+			},
+			expected: "open {dir}/b.yml: no such file or directory (include chain: {dir}/config.yml -> {dir}/a.yml -> {dir}/b.yml)",
+		},
+		{
+			desc: "invalid file",
+			files: map[string]string{
+				"config.yml": "include: a.toml\nname: foo\n",
+				"a.toml":     "name = ",
+			},
+			expected: "toml: line 1 (last key \"name\"): unexpected EOF; expected value (include chain: {dir}/config.yml -> {dir}/a.toml)",
+		},
+		{
+			desc: "invalid include value",
+			files: map[string]string{
+				"config.yml": "include: [1]\nname: foo\n",
+			},
+			expected: "invalid include value: 1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			dir := writeFiles(t, test.files)
+
+			err := Decode(filepath.Join(dir, "config.yml"), &includedConfig{})
+			require.Error(t, err)
+
+			assert.Equal(t, strings.ReplaceAll(test.expected, "{dir}", dir), err.Error())
+		})
+	}
+}
+
+func TestDecode_include_glob_no_match(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yml": "include: conf.d/*.yml\nname: foo\n",
+	})
+
+	element := &includedConfig{}
+	err := Decode(filepath.Join(dir, "config.yml"), element)
+	require.NoError(t, err)
+
+	assert.Equal(t, &includedConfig{Name: "foo"}, element)
+}
+
+func TestDecode_include_field(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yml": "include: [a.yml, b.yml]\nname: foo\n",
+	})
+
+	element := &struct {
+		Name    string
+		Include []string
+	}{}
+	err := Decode(filepath.Join(dir, "config.yml"), element)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Name)
+	assert.Equal(t, []string{"a.yml", "b.yml"}, element.Include)
+}
+
+func TestDecodeContent_include(t *testing.T) {
+	element := &struct {
+		Name    string
+		Include string
+	}{}
+	err := DecodeContent("include: db.yml\nname: foo\n", ".yml", element)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo", element.Name)
+	assert.Equal(t, "db.yml", element.Include)
+}
+
+func TestDecodeFilesWithOpts(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"system.yml":  "name: system\ndb:\n  host: db.system\n  port: 3306\ntags: [a]\n",
+		"user.toml":   "include = \"db.yml\"\nname = \"user\"\n",
+		"db.yml":      "db:\n  host: db.user\n",
+		"project.yml": "tags: [b, c]\n",
+	})
+
+	filePaths := []string{
+		filepath.Join(dir, "system.yml"),
+		filepath.Join(dir, "user.toml"),
+		filepath.Join(dir, "project.yml"),
+	}
+
+	origins := make(map[string]string)
+	opts := DecodeOpts{OnFill: func(path, key, filename string) {
+		origins[path] = filename
+	}}
+
+	element := &includedConfig{}
+	err := DecodeFilesWithOpts(filePaths, element, opts)
+	require.NoError(t, err)
+
+	expected := &includedConfig{
+		Name: "user",
+		DB:   &includedDB{Host: "db.user", Port: 3306},
+		Tags: []string{"b", "c"},
+	}
+	assert.Equal(t, expected, element)
+
+	expectedOrigins := map[string]string{
+		"name":    filepath.Join(dir, "user.toml"),
+		"db.host": filepath.Join(dir, "db.yml"),
+		"db.port": filepath.Join(dir, "system.yml"),
+		"tags":    filepath.Join(dir, "project.yml"),
+	}
+	assert.Equal(t, expectedOrigins, origins)
+}
@@ -0,0 +1,191 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decodeINI decodes an INI configuration.
+// Sections, dotted section names and dotted keys are mapped to nested maps,
+// [section "name"] to the entry name of the map section, and repeated keys to lists.
+// Values are kept as strings: comma-separated values are split when filling slices.
+func decodeINI(content []byte) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	section := data
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+
+		if text[0] == '[' {
+			path, err := parseINISection(text)
+			if err != nil {
+				return nil, fmt.Errorf("ini: line %d: %w", line, err)
+			}
+
+			section, err = iniTable(data, path)
+			if err != nil {
+				return nil, fmt.Errorf("ini: line %d: %w", line, err)
+			}
+			continue
+		}
+
+		key, value, err := parseINIEntry(text)
+		if err != nil {
+			return nil, fmt.Errorf("ini: line %d: %w", line, err)
+		}
+
+		path := strings.Split(key, ".")
+
+		table, err := iniTable(section, path[:len(path)-1])
+		if err != nil {
+			return nil, fmt.Errorf("ini: line %d: %w", line, err)
+		}
+
+		name := path[len(path)-1]
+		switch v := table[name].(type) {
+		case nil:
+			table[name] = value
+		case string:
+			table[name] = []interface{}{v, value}
+		case []interface{}:
+			table[name] = append(v, value)
+		default:
+			return nil, fmt.Errorf("ini: line %d: %q is already defined as a section", line, key)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// parseINISection parses a section header such as [server.ftp] or [notif "mail"].
+func parseINISection(text string) ([]string, error) {
+	if !strings.HasSuffix(text, "]") {
+		return nil, fmt.Errorf("invalid section header: %s", text)
+	}
+
+	header := strings.TrimSpace(text[1 : len(text)-1])
+
+	name, sub, hasSub := strings.Cut(header, " ")
+	if name == "" {
+		return nil, fmt.Errorf("invalid section header: %s", text)
+	}
+
+	path := strings.Split(name, ".")
+	for _, p := range path {
+		if p == "" {
+			return nil, fmt.Errorf("invalid section header: %s", text)
+		}
+	}
+
+	if hasSub {
+		sub = strings.TrimSpace(sub)
+
+		unquoted, err := strconv.Unquote(sub)
+		if err != nil || !strings.HasPrefix(sub, `"`) {
+			return nil, fmt.Errorf("invalid subsection name %s: must be double-quoted", sub)
+		}
+
+		path = append(path, unquoted)
+	}
+
+	return path, nil
+}
+
+// parseINIEntry parses a key = value or key: value line.
+func parseINIEntry(text string) (string, string, error) {
+	idx := strings.IndexAny(text, "=:")
+	if idx < 0 {
+		return "", "", fmt.Errorf("invalid line %q: expected key = value", text)
+	}
+
+	key := strings.TrimSpace(text[:idx])
+	if key == "" {
+		return "", "", fmt.Errorf("invalid line %q: missing key", text)
+	}
+
+	value := strings.TrimSpace(text[idx+1:])
+
+	switch {
+	case strings.HasPrefix(value, `"`):
+		end := closingQuote(value)
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted value for %s", key)
+		}
+
+		unquoted, err := strconv.Unquote(value[:end+1])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid quoted value for %s: %w", key, err)
+		}
+
+		return key, unquoted, checkINIComment(key, value[end+1:])
+
+	case strings.HasPrefix(value, "'"):
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated quoted value for %s", key)
+		}
+
+		return key, value[1 : end+1], checkINIComment(key, value[end+2:])
+
+	default:
+		for i := 1; i < len(value); i++ {
+			if (value[i] == ';' || value[i] == '#') && (value[i-1] == ' ' || value[i-1] == '\t') {
+				value = strings.TrimSpace(value[:i])
+				break
+			}
+		}
+
+		return key, value, nil
+	}
+}
+
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// checkINIComment checks that only a comment follows a quoted value.
+func checkINIComment(key, rest string) error {
+	rest = strings.TrimSpace(rest)
+	if rest == "" || rest[0] == ';' || rest[0] == '#' {
+		return nil
+	}
+	return fmt.Errorf("unexpected %q after the quoted value of %s", rest, key)
+}
+
+// iniTable returns the nested map at path in data, creating it if needed.
+func iniTable(data map[string]interface{}, path []string) (map[string]interface{}, error) {
+	table := data
+	for _, name := range path {
+		switch v := table[name].(type) {
+		case nil:
+			sub := make(map[string]interface{})
+			table[name] = sub
+			table = sub
+		case map[string]interface{}:
+			table = v
+		default:
+			return nil, fmt.Errorf("%q is already defined as a value", name)
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,210 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeINI(t *testing.T) {
+	content := `
+; comment
+name = myapp ; inline comment
+# comment
+title = "Hello, \"world\"" ; comment
+path = 'C:\temp'
+url: http://example.com/#anchor
+
+[server]
+host = localhost
+
+[server.ftp]
+port = 21
+sources = /
+sources = /tmp
+tls.enabled = true
+
+[notif "mail"]
+host = smtp.example.com
+
+[notif "webhook"]
+endpoint = http://webhook
+`
+
+	data, err := decodeINI([]byte(content))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":  "myapp",
+		"title": `Hello, "world"`,
+		"path":  `C:\temp`,
+		"url":   "http://example.com/#anchor",
+		"server": map[string]interface{}{
+			"host": "localhost",
+			"ftp": map[string]interface{}{
+				"port":    "21",
+				"sources": []interface{}{"/", "/tmp"},
+				"tls":     map[string]interface{}{"enabled": "true"},
+			},
+		},
+		"notif": map[string]interface{}{
+			"mail":    map[string]interface{}{"host": "smtp.example.com"},
+			"webhook": map[string]interface{}{"endpoint": "http://webhook"},
+		},
+	}
+	assert.Equal(t, expected, data)
+}
+
+func Test_decodeINI_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "invalid section",
+			content:  "foo = bar\n[server\n",
+			expected: "ini: line 2: invalid section header: [server",
+		},
+		{
+			desc:     "empty section name",
+			content:  "[server..ftp]\n",
+			expected: "ini: line 1: invalid section header: [server..ftp]",
+		},
+		{
+			desc:     "unquoted subsection",
+			content:  "[notif mail]\n",
+			expected: "ini: line 1: invalid subsection name mail: must be double-quoted",
+		},
+		{
+			desc:     "missing value",
+			content:  "\n\nfoo\n",
+			expected: `ini: line 3: invalid line "foo": expected key = value`,
+		},
+		{
+			desc:     "missing key",
+			content:  "= bar\n",
+			expected: `ini: line 1: invalid line "= bar": missing key`,
+		},
+		{
+			desc:     "unterminated quote",
+			content:  "foo = \"bar\n",
+			expected: "ini: line 1: unterminated quoted value for foo",
+		},
+		{
+			desc:     "data after quote",
+			content:  "foo = \"bar\" baz\n",
+			expected: `ini: line 1: unexpected "baz" after the quoted value of foo`,
+		},
+		{
+			desc:     "section defined as value",
+			content:  "server = foo\n[server]\n",
+			expected: `ini: line 2: "server" is already defined as a value`,
+		},
+		{
+			desc:     "value defined as section",
+			content:  "[server]\n[main]\nserver.x = 1\n[other]\n[server.ftp]\n[root]\n",
+			expected: "",
+		},
+		{
+			desc:     "key defined as section",
+			content:  "[server.ftp]\n[server]\nftp = foo\n",
+			expected: `ini: line 3: "ftp" is already defined as a section`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := decodeINI([]byte(test.content))
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeContent_INI(t *testing.T) {
+	type ftp struct {
+		Host    string
+		Port    int
+		Sources []string
+		Exclude []string
+		Retries []int
+	}
+
+	type notif struct {
+		Host string
+	}
+
+	type config struct {
+		Name  string
+		Title string
+		FTP   *ftp
+		Yi    *Yi `file:"allowEmpty"`
+		Notif map[string]notif
+	}
+
+	content := `
+name = myapp
+title = Hello, world
+
+[ftp]
+host = localhost
+port = 21
+sources = /, /tmp
+exclude = \.nfo$
+exclude = \.txt$
+retries = 1,2,3
+
+[yi]
+
+[notif "mail"]
+host = smtp.example.com
+`
+
+	element := &config{}
+	err := DecodeContent(content, ".ini", element)
+	require.NoError(t, err)
+
+	expected := &config{
+		Name:  "myapp",
+		Title: "Hello, world",
+		FTP: &ftp{
+			Host:    "localhost",
+			Port:    21,
+			Sources: []string{"/", "/tmp"},
+			Exclude: []string{`\.nfo$`, `\.txt$`},
+			Retries: []int{1, 2, 3},
+		},
+		Yi: &Yi{
+			Foo: "foo",
+			Fii: "fii",
+		},
+		Notif: map[string]notif{
+			"mail": {Host: "smtp.example.com"},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_INI(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yml": "include: [db.ini]\nname: myapp\n",
+		"db.ini":     "tags = a, b\n\n[db]\nhost = db.local\nport = 5432\n",
+	})
+
+	element := &includedConfig{}
+	err := Decode(filepath.Join(dir, "config.yml"), element)
+	require.NoError(t, err)
+
+	expected := &includedConfig{
+		Name: "myapp",
+		DB:   &includedDB{Host: "db.local", Port: 5432},
+		Tags: []string{"a", "b"},
+	}
+	assert.Equal(t, expected, element)
+}
@@ -0,0 +1,128 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+var varNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// interpolateNode expands the environment variable references in the values of the children of node.
+func interpolateNode(node *parser.Node, path string) error {
+	for _, child := range node.Children {
+		childPath := child.Name
+		if path != "" {
+			childPath = path + "." + child.Name
+		}
+
+		if child.Value != "" {
+			value, err := interpolate(child.Value, os.LookupEnv)
+			if err != nil {
+				return fmt.Errorf("failed to interpolate %s: %w", childPath, err)
+			}
+			child.Value = value
+		}
+
+		if err := interpolateNode(child, childPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// interpolate expands the ${VAR}, ${VAR:-default} and ${VAR:?error} references in s.
+// $$ is an escaped $, and a $ that is not followed by { is kept as is.
+func interpolate(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference: %s", s[i:])
+			}
+
+			value, err := expand(s[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(value)
+			i = end
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func expand(expr string, lookup func(string) (string, bool)) (string, error) {
+	name, arg, hasOp := strings.Cut(expr, ":")
+
+	if !varNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("invalid variable name: %q", name)
+	}
+
+	value, ok := lookup(name)
+	if !hasOp {
+		return value, nil
+	}
+
+	if len(arg) == 0 || (arg[0] != '-' && arg[0] != '?') {
+		return "", fmt.Errorf("invalid variable reference: ${%s}", expr)
+	}
+
+	if ok && value != "" {
+		return value, nil
+	}
+
+	fallback, err := interpolate(arg[1:], lookup)
+	if err != nil {
+		return "", err
+	}
+
+	if arg[0] == '?' {
+		if fallback == "" {
+			fallback = "required variable is not set"
+		}
+		return "", fmt.Errorf("%s: %s", name, fallback)
+	}
+
+	return fallback, nil
+}
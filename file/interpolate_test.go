@@ -0,0 +1,121 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_interpolate(t *testing.T) {
+	environ := map[string]string{
+		"FOO":   "foo",
+		"EMPTY": "",
+	}
+
+	lookup := func(name string) (string, bool) {
+		v, ok := environ[name]
+		return v, ok
+	}
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+		wantErr  string
+	}{
+		{
+			desc:     "no reference",
+			value:    "foo",
+			expected: "foo",
+		},
+		{
+			desc:     "variable",
+			value:    "${FOO}",
+			expected: "foo",
+		},
+		{
+			desc:     "variable within text",
+			value:    "http://${FOO}:8080/${FOO}",
+			expected: "http://foo:8080/foo",
+		},
+		{
+			desc:     "unset variable",
+			value:    "a${BAR}b",
+			expected: "ab",
+		},
+		{
+			desc:     "default of set variable",
+			value:    "${FOO:-bar}",
+			expected: "foo",
+		},
+		{
+			desc:     "default of unset variable",
+			value:    "${BAR:-localhost}",
+			expected: "localhost",
+		},
+		{
+			desc:     "default of empty variable",
+			value:    "${EMPTY:-localhost}",
+			expected: "localhost",
+		},
+		{
+			desc:     "nested default",
+			value:    "${BAR:-${FOO}}",
+			expected: "foo",
+		},
+		{
+			desc:     "required set variable",
+			value:    "${FOO:?FOO must be set}",
+			expected: "foo",
+		},
+		{
+			desc:    "required unset variable",
+			value:   "${BAR:?BAR must be set}",
+			wantErr: "BAR: BAR must be set",
+		},
+		{
+			desc:    "required unset variable without message",
+			value:   "${BAR:?}",
+			wantErr: "BAR: required variable is not set",
+		},
+		{
+			desc:     "escaped",
+			value:    "$${FOO} $$FOO",
+			expected: "${FOO} $FOO",
+		},
+		{
+			desc:     "dollar without brace",
+			value:    `\.nfo$ $FOO`,
+			expected: `\.nfo$ $FOO`,
+		},
+		{
+			desc:    "unterminated",
+			value:   "${FOO",
+			wantErr: "unterminated variable reference: ${FOO",
+		},
+		{
+			desc:    "invalid name",
+			value:   "${1FOO}",
+			wantErr: `invalid variable name: "1FOO"`,
+		},
+		{
+			desc:    "invalid operator",
+			value:   "${FOO:+bar}",
+			wantErr: "invalid variable reference: ${FOO:+bar}",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			value, err := interpolate(test.value, lookup)
+			if test.wantErr != "" {
+				require.EqualError(t, err, test.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// decodeJSON decodes a JSON object.
+// It rejects duplicate keys, keeps the integers that don't fit in a float64 as is,
+// and reports the line and the column of the syntax errors.
+func decodeJSON(content []byte) (map[string]interface{}, error) {
+	return newJSONDecoder(content, content, nil).decode()
+}
+
+func newJSONDecoder(content, source []byte, offsets []int) *jsonDecoder {
+	d := &jsonDecoder{
+		content: content,
+		source:  source,
+		offsets: offsets,
+		dec:     json.NewDecoder(bytes.NewReader(content)),
+	}
+	d.dec.UseNumber()
+
+	return d
+}
+
+func (d *jsonDecoder) decode() (map[string]interface{}, error) {
+	// an empty content, e.g. with only comments once converted from JSONC, holds no configuration
+	if len(bytes.TrimSpace(d.content)) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	tok, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok != json.Delim('{') {
+		return nil, d.errorf("expected an object, got %s", tokenString(tok))
+	}
+
+	data, err := d.object("")
+	if err != nil {
+		return nil, err
+	}
+
+	offset := d.dec.InputOffset()
+	if _, err = d.dec.Token(); !errors.Is(err, io.EOF) {
+		line, column := d.position(d.skipSpaces(offset))
+		return nil, fmt.Errorf("json: line %d, column %d: unexpected data after the top-level object", line, column)
+	}
+
+	return data, nil
+}
+
+type jsonDecoder struct {
+	content []byte
+	// source is the content the errors refer to, offsets maps the offsets of content to the ones of source if they differ.
+	source  []byte
+	offsets []int
+	dec     *json.Decoder
+}
+
+func (d *jsonDecoder) token() (json.Token, error) {
+	tok, err := d.dec.Token()
+	if err == nil {
+		return tok, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// the offset is the one after the invalid character, or the end of the content
+		offset := syntaxErr.Offset
+		if !strings.HasPrefix(syntaxErr.Error(), "unexpected end") {
+			offset--
+		}
+		line, column := d.position(offset)
+		return nil, fmt.Errorf("json: line %d, column %d: %s", line, column, syntaxErr.Error())
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		line, column := d.position(int64(len(d.content)))
+		return nil, fmt.Errorf("json: line %d, column %d: unexpected end of JSON input", line, column)
+	}
+
+	return nil, d.errorf("%s", err)
+}
+
+// object decodes the members of an object whose opening brace has been read.
+func (d *jsonDecoder) object(path string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	for d.dec.More() {
+		offset := d.dec.InputOffset()
+
+		tok, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, d.errorf("expected a key, got %s", tokenString(tok))
+		}
+
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		if _, exists := data[key]; exists {
+			line, column := d.position(d.skipSpaces(offset))
+			return nil, fmt.Errorf("json: line %d, column %d: duplicate key %q", line, column, keyPath)
+		}
+
+		data[key], err = d.value(keyPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// closing brace
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// array decodes the items of an array whose opening bracket has been read.
+func (d *jsonDecoder) array(path string) ([]interface{}, error) {
+	list := make([]interface{}, 0)
+
+	for i := 0; d.dec.More(); i++ {
+		item, err := d.value(path + "[" + strconv.Itoa(i) + "]")
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+
+	// closing bracket
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (d *jsonDecoder) value(path string) (interface{}, error) {
+	tok, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return d.object(path)
+		case '[':
+			return d.array(path)
+		default:
+			return nil, d.errorf("unexpected %s", tokenString(tok))
+		}
+	case json.Number:
+		return number(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// number converts n to an int, an uint64 or a float64 if it can without losing precision.
+func number(n json.Number) interface{} {
+	s := n.String()
+
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u
+		}
+		return s
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+
+	return s
+}
+
+func (d *jsonDecoder) errorf(format string, a ...interface{}) error {
+	line, column := d.position(d.dec.InputOffset())
+	return fmt.Errorf("json: line %d, column %d: %s", line, column, fmt.Sprintf(format, a...))
+}
+
+// position returns the line and the column in source of the byte of content at offset, both starting at 1.
+func (d *jsonDecoder) position(offset int64) (int, int) {
+	if offset > int64(len(d.content)) {
+		offset = int64(len(d.content))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if d.offsets != nil {
+		if offset < int64(len(d.offsets)) {
+			offset = int64(d.offsets[offset])
+		} else {
+			offset = int64(len(d.source))
+		}
+	}
+
+	before := d.source[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// skipSpaces returns the offset of the first character after the whitespaces and the separators following offset.
+func (d *jsonDecoder) skipSpaces(offset int64) int64 {
+	for offset < int64(len(d.content)) && strings.ContainsRune(" \t\r\n,:", rune(d.content[offset])) {
+		offset++
+	}
+	return offset
+}
+
+func tokenString(tok json.Token) string {
+	switch v := tok.(type) {
+	case json.Delim:
+		return strconv.Quote(v.String())
+	case string:
+		return "string " + strconv.Quote(v)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,113 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeJSON(t *testing.T) {
+	content := `{
+  "name": "foo",
+  "enabled": true,
+  "nothing": null,
+  "int": 42,
+  "negative": -42,
+  "big": 18446744073709551615,
+  "huge": 123456789012345678901234567890,
+  "float": 1.5,
+  "exp": 1e3,
+  "list": [1, "a", {"b": 2}],
+  "empty": [],
+  "sub": {"foo": {"bar": "baz"}}
+}`
+
+	data, err := decodeJSON([]byte(content))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name":     "foo",
+		"enabled":  true,
+		"nothing":  nil,
+		"int":      42,
+		"negative": -42,
+		"big":      uint64(18446744073709551615),
+		"huge":     "123456789012345678901234567890",
+		"float":    1.5,
+		"exp":      float64(1000),
+		"list":     []interface{}{1, "a", map[string]interface{}{"b": 2}},
+		"empty":    []interface{}{},
+		"sub":      map[string]interface{}{"foo": map[string]interface{}{"bar": "baz"}},
+	}
+	assert.Equal(t, expected, data)
+}
+
+func Test_decodeJSON_empty(t *testing.T) {
+	for _, content := range []string{"", " \n\t\n"} {
+		data, err := decodeJSON([]byte(content))
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	}
+}
+
+func Test_decodeJSON_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "not an object",
+			content:  `["foo"]`,
+			expected: `json: line 1, column 2: expected an object, got "["`,
+		},
+		{
+			desc:     "yaml syntax",
+			content:  "{\n  \"foo\": \"bar\",\n  bar: baz\n}",
+			expected: "json: line 3, column 3: invalid character 'b' looking for beginning of value",
+		},
+		{
+			desc:     "comment",
+			content:  "{\n  # comment\n  \"foo\": \"bar\"\n}",
+			expected: "json: line 2, column 3: invalid character '#' looking for beginning of value",
+		},
+		{
+			desc:     "missing comma",
+			content:  "{\n  \"foo\": \"bar\"\n  \"bar\": \"baz\"\n}",
+			expected: "json: line 3, column 3: invalid character '\"' after object key:value pair",
+		},
+		{
+			desc:     "duplicate key",
+			content:  "{\n  \"foo\": \"bar\",\n  \"foo\": \"baz\"\n}",
+			expected: `json: line 3, column 3: duplicate key "foo"`,
+		},
+		{
+			desc:     "nested duplicate key",
+			content:  `{"foo": {"bar": [{"baz": 1, "baz": 2}]}}`,
+			expected: `json: line 1, column 29: duplicate key "foo.bar[0].baz"`,
+		},
+		{
+			desc:     "unterminated",
+			content:  `{"foo": {"bar": 1}`,
+			expected: "json: line 1, column 19: unexpected end of JSON input",
+		},
+		{
+			desc:     "invalid last character",
+			content:  `{"foo": x`,
+			expected: "json: line 1, column 9: invalid character 'x' looking for beginning of value",
+		},
+		{
+			desc:     "trailing data",
+			content:  `{"foo": 1} {"bar": 2}`,
+			expected: "json: line 1, column 12: unexpected data after the top-level object",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := decodeJSON([]byte(test.content))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// decodeJSONC decodes a JSONC or JSON5 object.
+// On top of JSON, it supports // and /* */ comments, trailing commas, unquoted keys and single-quoted strings.
+// The content is converted to JSON before being decoded, the errors refer to the original content.
+func decodeJSONC(content []byte) (map[string]interface{}, error) {
+	s := &jsoncScanner{src: content}
+	if err := s.scan(); err != nil {
+		return nil, err
+	}
+
+	return newJSONDecoder(s.out, content, s.offsets).decode()
+}
+
+// jsoncScanner converts a JSONC or JSON5 content to JSON.
+// offsets holds the offset in src of each byte written to out.
+type jsoncScanner struct {
+	src     []byte
+	pos     int
+	out     []byte
+	offsets []int
+}
+
+func (s *jsoncScanner) scan() error {
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+
+		switch {
+		case c == '/' && s.pos+1 < len(s.src) && (s.src[s.pos+1] == '/' || s.src[s.pos+1] == '*'):
+			if err := s.skipComment(); err != nil {
+				return err
+			}
+		case c == '"':
+			if err := s.doubleQuoted(); err != nil {
+				return err
+			}
+		case c == '\'':
+			if err := s.singleQuoted(); err != nil {
+				return err
+			}
+		case c == ',':
+			// trailing comma
+			if next := s.next(s.pos + 1); next < len(s.src) && (s.src[next] == '}' || s.src[next] == ']') {
+				s.pos++
+				continue
+			}
+			s.write(c, s.pos)
+			s.pos++
+		case isIdentifierStart(c):
+			s.identifier()
+		default:
+			s.write(c, s.pos)
+			s.pos++
+		}
+	}
+
+	return nil
+}
+
+// skipComment skips the comment starting at pos.
+func (s *jsoncScanner) skipComment() error {
+	start := s.pos
+
+	if s.src[s.pos+1] == '/' {
+		if end := bytes.IndexByte(s.src[s.pos:], '\n'); end >= 0 {
+			s.pos += end
+		} else {
+			s.pos = len(s.src)
+		}
+		return nil
+	}
+
+	end := bytes.Index(s.src[s.pos+2:], []byte("*/"))
+	if end < 0 {
+		return s.errorf(start, "unterminated comment")
+	}
+	s.pos += end + 4
+
+	return nil
+}
+
+// next returns the offset of the first character after the whitespaces and the comments following offset.
+func (s *jsoncScanner) next(offset int) int {
+	for offset < len(s.src) {
+		switch {
+		case bytes.IndexByte([]byte(" \t\r\n"), s.src[offset]) >= 0:
+			offset++
+		case bytes.HasPrefix(s.src[offset:], []byte("//")):
+			end := bytes.IndexByte(s.src[offset:], '\n')
+			if end < 0 {
+				return len(s.src)
+			}
+			offset += end
+		case bytes.HasPrefix(s.src[offset:], []byte("/*")):
+			end := bytes.Index(s.src[offset+2:], []byte("*/"))
+			if end < 0 {
+				return len(s.src)
+			}
+			offset += end + 4
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// doubleQuoted copies the double-quoted string starting at pos.
+func (s *jsoncScanner) doubleQuoted() error {
+	start := s.pos
+	s.write('"', s.pos)
+	s.pos++
+
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+		s.write(c, s.pos)
+		s.pos++
+
+		switch c {
+		case '\\':
+			if s.pos < len(s.src) {
+				s.write(s.src[s.pos], s.pos)
+				s.pos++
+			}
+		case '"':
+			return nil
+		}
+	}
+
+	return s.errorf(start, "unterminated string")
+}
+
+// singleQuoted converts the single-quoted string starting at pos to a double-quoted one.
+func (s *jsoncScanner) singleQuoted() error {
+	start := s.pos
+	s.write('"', s.pos)
+	s.pos++
+
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+
+		switch c {
+		case '\\':
+			if s.pos+1 < len(s.src) && s.src[s.pos+1] == '\'' {
+				s.write('\'', s.pos)
+				s.pos += 2
+				continue
+			}
+			s.write(c, s.pos)
+			s.pos++
+			if s.pos < len(s.src) {
+				s.write(s.src[s.pos], s.pos)
+				s.pos++
+			}
+		case '"':
+			s.write('\\', s.pos)
+			s.write('"', s.pos)
+			s.pos++
+		case '\'':
+			s.write('"', s.pos)
+			s.pos++
+			return nil
+		default:
+			s.write(c, s.pos)
+			s.pos++
+		}
+	}
+
+	return s.errorf(start, "unterminated string")
+}
+
+// identifier quotes the identifier starting at pos if it is a key, or copies it otherwise.
+func (s *jsoncScanner) identifier() {
+	start := s.pos
+	for s.pos < len(s.src) && isIdentifierChar(s.src[s.pos]) {
+		s.pos++
+	}
+
+	next := s.next(s.pos)
+	isKey := next < len(s.src) && s.src[next] == ':'
+
+	if isKey {
+		s.write('"', start)
+	}
+	for i := start; i < s.pos; i++ {
+		s.write(s.src[i], i)
+	}
+	if isKey {
+		s.write('"', s.pos-1)
+	}
+}
+
+func (s *jsoncScanner) write(c byte, offset int) {
+	s.out = append(s.out, c)
+	s.offsets = append(s.offsets, offset)
+}
+
+func (s *jsoncScanner) errorf(offset int, format string, a ...interface{}) error {
+	line, column := newJSONDecoder(s.src, s.src, nil).position(int64(offset))
+	return fmt.Errorf("json: line %d, column %d: %s", line, column, fmt.Sprintf(format, a...))
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || c >= '0' && c <= '9'
+}
@@ -0,0 +1,126 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeJSONC(t *testing.T) {
+	content := `
+// the name of the application
+{
+  name: 'my"app',
+  /* the database,
+     with a block comment */
+  db: {
+    "host": 'db.local', // trailing comment
+    port: 5432,
+    $tags: ['a', 'it\'s', "b", ],
+  },
+  url: "http://example.com/*path*/",
+  enabled: true,
+  size: 1e3,
+}
+`
+
+	data, err := decodeJSONC([]byte(content))
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"name": `my"app`,
+		"db": map[string]interface{}{
+			"host":  "db.local",
+			"port":  5432,
+			"$tags": []interface{}{"a", "it's", "b"},
+		},
+		"url":     "http://example.com/*path*/",
+		"enabled": true,
+		"size":    float64(1000),
+	}
+	assert.Equal(t, expected, data)
+}
+
+func Test_decodeJSONC_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "unterminated comment",
+			content:  "{\n  a: 1 /* comment\n}",
+			expected: "json: line 2, column 8: unterminated comment",
+		},
+		{
+			desc:     "unterminated string",
+			content:  "{\n  a: 'foo\n}",
+			expected: "json: line 2, column 6: unterminated string",
+		},
+		{
+			desc:     "invalid value after unquoted key",
+			content:  "{\n  foo: 'bar',\n  bar: baz\n}",
+			expected: "json: line 3, column 8: invalid character 'b' looking for beginning of value",
+		},
+		{
+			desc:     "duplicate key",
+			content:  "{\n  // comment\n  a: 1,\n  'a': 2\n}",
+			expected: `json: line 4, column 3: duplicate key "a"`,
+		},
+		{
+			desc:     "unexpected end",
+			content:  "{\n  a: [1, 2,\n",
+			expected: "json: line 3, column 1: unexpected end of JSON input",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := decodeJSONC([]byte(test.content))
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestDecodeContent_JSONC(t *testing.T) {
+	type config struct {
+		Name    string
+		Sources []string
+	}
+
+	content := `{
+  // comment
+  name: 'myapp',
+  sources: ['/', '/tmp',],
+}`
+
+	for _, extension := range []string{".jsonc", ".json5", ".JSON5"} {
+		t.Run(extension, func(t *testing.T) {
+			element := &config{}
+			err := DecodeContent(content, extension, element)
+			require.NoError(t, err)
+
+			expected := &config{Name: "myapp", Sources: []string{"/", "/tmp"}}
+			assert.Equal(t, expected, element)
+		})
+	}
+}
+
+func TestDecode_JSONC(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.jsonc": "{\n  // includes\n  include: ['db.json5'],\n  name: 'myapp',\n}\n",
+		"db.json5":     "{db: {host: 'db.local', port: 5432,},}\n",
+	})
+
+	element := &includedConfig{}
+	err := Decode(filepath.Join(dir, "config.jsonc"), element)
+	require.NoError(t, err)
+
+	expected := &includedConfig{
+		Name: "myapp",
+		DB:   &includedDB{Host: "db.local", Port: 5432},
+	}
+	assert.Equal(t, expected, element)
+}
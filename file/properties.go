@@ -0,0 +1,174 @@
+package file
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// decodeProperties decodes a Java .properties content into labels.
+// Lines starting with # or ! are comments, a key is separated from its value by =, : or whitespaces,
+// a line ending with a backslash continues on the next line, and the \t, \n, \r, \f, \uXXXX escapes are supported.
+func decodeProperties(content []byte) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		for continues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continues(line) {
+			line = line[:len(line)-1]
+		}
+
+		key, value := splitProperty(line)
+
+		var err error
+		if key, err = unescapeProperty(key); err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", lineNumber, err)
+		}
+		if value, err = unescapeProperty(value); err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", lineNumber, err)
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// continues reports whether line ends with an odd number of backslashes.
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits a logical line into its escaped key and value.
+func splitProperty(line string) (string, string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+
+	key := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	return key, rest
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid unicode escape: %s", s[i-1:])
+			}
+
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape: %s", s[i-1:i+5])
+			}
+
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// getPropertiesIncludes removes the include key from labels and returns its comma-separated paths.
+func getPropertiesIncludes(labels map[string]string) []string {
+	var includes []string
+	for key, value := range labels {
+		if !strings.EqualFold(key, IncludeKey) {
+			continue
+		}
+
+		delete(labels, key)
+
+		for _, include := range strings.Split(value, ",") {
+			if include = strings.TrimSpace(include); include != "" {
+				includes = append(includes, include)
+			}
+		}
+	}
+
+	return includes
+}
+
+// propertiesToNode converts the labels to a tree of nodes with the label parser.
+// If filters is not empty, it skips the labels whose first element is not among filters.
+func propertiesToNode(labels map[string]string, filters ...string) (*parser.Node, error) {
+	prefixed := make(map[string]string, len(labels))
+
+	for key, value := range labels {
+		name, _, _ := strings.Cut(key, ".")
+		name, _, _ = strings.Cut(name, "[")
+
+		if len(filters) > 0 && !containsFold(filters, name) {
+			continue
+		}
+
+		prefixed[parser.DefaultRootName+"."+key] = value
+	}
+
+	if len(prefixed) == 0 {
+		return &parser.Node{Name: parser.DefaultRootName}, nil
+	}
+
+	return parser.DecodeToNode(prefixed, parser.DefaultRootName)
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
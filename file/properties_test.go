@@ -0,0 +1,145 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeProperties(t *testing.T) {
+	content := `# comment
+! comment
+   # indented comment
+
+name=myapp
+server.host = localhost
+server.port: 8080
+server.path   /api
+empty =
+key\ with\ spaces = value with spaces  
+escaped\=key = a\=b\:c
+unicode = caf\u00e9
+tabs = a\tb\nc
+multiline = line1, \
+            line2, \
+            line3
+path = C:\\temp\\
+trailing = foo\
+`
+
+	labels, err := decodeProperties([]byte(content))
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"name":            "myapp",
+		"server.host":     "localhost",
+		"server.port":     "8080",
+		"server.path":     "/api",
+		"empty":           "",
+		"key with spaces": "value with spaces  ",
+		"escaped=key":     "a=b:c",
+		"unicode":         "café",
+		"tabs":            "a\tb\nc",
+		"multiline":       "line1, line2, line3",
+		"path":            `C:\temp\`,
+		"trailing":        "foo",
+	}
+	assert.Equal(t, expected, labels)
+}
+
+func Test_decodeProperties_errors(t *testing.T) {
+	_, err := decodeProperties([]byte("foo = bar\nbar = \\u12"))
+	require.EqualError(t, err, `properties: line 2: invalid unicode escape: \u12`)
+
+	_, err = decodeProperties([]byte("foo = \\u12zz"))
+	require.EqualError(t, err, `properties: line 1: invalid unicode escape: \u12zz`)
+}
+
+func TestDecodeContent_properties(t *testing.T) {
+	type backend struct {
+		URL    string
+		Weight int
+	}
+
+	type notif struct {
+		Host string
+	}
+
+	type config struct {
+		Name     string
+		Ignored  string `file:"-"`
+		Yi       *Yi    `file:"allowEmpty"`
+		Sources  []string
+		Backends []backend
+		Notif    map[string]notif
+	}
+
+	content := `
+name = myapp
+ignored = foo
+unknown = bar
+yi =
+sources = /, /tmp
+backends[0].url = http://a
+backends[0].weight = 1
+backends[1].url = http://b
+notif.mail.host = smtp.example.com
+`
+
+	element := &config{}
+	err := DecodeContent(content, ".properties", element)
+	require.NoError(t, err)
+
+	expected := &config{
+		Name: "myapp",
+		Yi: &Yi{
+			Foo: "foo",
+			Fii: "fii",
+		},
+		Sources: []string{"/", "/tmp"},
+		Backends: []backend{
+			{URL: "http://a", Weight: 1},
+			{URL: "http://b"},
+		},
+		Notif: map[string]notif{
+			"mail": {Host: "smtp.example.com"},
+		},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestDecode_properties(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.properties":     "include = db.yml, notif/*.properties\nname = myapp\ndb.port = 5432\n",
+		"db.yml":                "db:\n  host: db.local\n  port: 3306\n",
+		"notif/mail.properties": "notif.mail.endpoint = smtp://mail\n",
+	})
+
+	origins := make(map[string]string)
+	opts := DecodeOpts{OnFill: func(path, key, filename string) {
+		origins[path] = filename
+	}}
+
+	element := &includedConfig{}
+	err := DecodeWithOpts(filepath.Join(dir, "config.properties"), element, opts)
+	require.NoError(t, err)
+
+	expected := &includedConfig{
+		Name: "myapp",
+		DB:   &includedDB{Host: "db.local", Port: 5432},
+		Notif: map[string]*includedNotif{
+			"mail": {Endpoint: "smtp://mail"},
+		},
+	}
+	assert.Equal(t, expected, element)
+
+	expectedOrigins := map[string]string{
+		"name":                filepath.Join(dir, "config.properties"),
+		"db.host":             filepath.Join(dir, "db.yml"),
+		"db.port":             filepath.Join(dir, "config.properties"),
+		"notif.mail.endpoint": filepath.Join(dir, "notif", "mail.properties"),
+	}
+	assert.Equal(t, expectedOrigins, origins)
+}
@@ -0,0 +1,333 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/crazy-max/gonfig/generator"
+	"gopkg.in/yaml.v3"
+)
+
+// SampleOpts holds options used when generating a sample configuration.
+type SampleOpts struct {
+	// CommentUndefined comments out the keys that have no default value.
+	CommentUndefined bool
+}
+
+// GenerateSample generates a sample configuration file in the format of the file extension (.toml, .yml or .yaml)
+// for the type of the given element, which must be a pointer to a struct.
+// The values are the defaults set by generator.Generate, the descriptions are written as comments,
+// and the map keys are the parser.MapNamePlaceholder placeholder.
+func GenerateSample(element interface{}, extension string) ([]byte, error) {
+	return GenerateSampleWithOpts(element, extension, SampleOpts{})
+}
+
+// GenerateSampleWithOpts generates a sample configuration file for the type of the given element using opts.
+func GenerateSampleWithOpts(element interface{}, extension string, opts SampleOpts) ([]byte, error) {
+	rType := reflect.TypeOf(element)
+	if rType == nil || rType.Kind() != reflect.Pointer || rType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported element type: %T", element)
+	}
+
+	sample := reflect.New(rType.Elem())
+	generator.Generate(sample.Interface())
+
+	// the undefined values are written as empty values
+	root, err := EncodeToMap(sample.Interface(), EncodeOpts{
+		OnValue: func(leaf Leaf) interface{} {
+			switch {
+			case leaf.Encoded != nil:
+				return leaf.Encoded
+			case leaf.Value.Kind() == reflect.Slice:
+				return []interface{}{}
+			default:
+				return ""
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := sampleWriter{SampleOpts: opts}
+
+	var lines []string
+	switch strings.ToLower(extension) {
+	case ".toml":
+		lines, err = w.toml(root, nil)
+	case ".yml", ".yaml":
+		lines, err = w.yaml(root, true)
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", extension)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.TrimLeft(strings.Join(lines, "\n"), "\n") + "\n"), nil
+}
+
+// tables returns the maps of a list of maps.
+func tables(value interface{}) ([]*OrderedMap, bool) {
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, false
+	}
+
+	maps := make([]*OrderedMap, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(*OrderedMap)
+		if !ok {
+			return nil, false
+		}
+		maps = append(maps, m)
+	}
+	return maps, true
+}
+
+type sampleWriter struct {
+	SampleOpts
+}
+
+// yaml returns the lines of the YAML representation of m.
+func (w sampleWriter) yaml(m *OrderedMap, top bool) ([]string, error) {
+	var lines []string
+
+	for i, e := range m.entries {
+		if top && i > 0 {
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, descriptionLines(e.description)...)
+
+		key, err := yamlScalar(e.key)
+		if err != nil {
+			return nil, err
+		}
+
+		// the entries of a commented out map are not commented out again
+		child := w
+		child.CommentUndefined = w.CommentUndefined && !e.zero
+
+		var body []string
+
+		switch v := sampleEntryValue(e).(type) {
+		case *OrderedMap:
+			if len(v.entries) == 0 {
+				body = []string{key + ": {}"}
+				break
+			}
+
+			children, err := child.yaml(v, false)
+			if err != nil {
+				return nil, err
+			}
+
+			body = append([]string{key + ":"}, indentLines(children, "  ")...)
+
+		case []*OrderedMap:
+			body = []string{key + ":"}
+
+			for _, item := range v {
+				children, err := child.yaml(item, false)
+				if err != nil {
+					return nil, err
+				}
+
+				switch {
+				case len(children) == 0:
+					body = append(body, "  - {}")
+				case strings.HasPrefix(children[0], "#"):
+					body = append(body, "  -")
+					body = append(body, indentLines(children, "    ")...)
+				default:
+					body = append(body, "  - "+children[0])
+					body = append(body, indentLines(children[1:], "    ")...)
+				}
+			}
+
+		default:
+			value, err := yamlScalar(v)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := v.([]interface{}); ok && strings.Contains(value, "\n") {
+				body = append([]string{key + ":"}, indentLines(strings.Split(value, "\n"), "  ")...)
+			} else {
+				body = strings.Split(key+": "+value, "\n")
+			}
+		}
+
+		if w.CommentUndefined && e.zero {
+			body = commentLines(body)
+		}
+
+		lines = append(lines, body...)
+	}
+
+	return lines, nil
+}
+
+// toml returns the lines of the TOML representation of m, the table at path.
+// The values are written before the tables.
+func (w sampleWriter) toml(m *OrderedMap, path []string) ([]string, error) {
+	var lines []string
+
+	for _, e := range m.entries {
+		value := sampleEntryValue(e)
+		switch value.(type) {
+		case *OrderedMap, []*OrderedMap:
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{e.key: value}); err != nil {
+			return nil, err
+		}
+
+		body := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+		if w.CommentUndefined && e.zero {
+			body = commentLines(body)
+		}
+
+		if len(lines) > 0 && e.description != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, descriptionLines(e.description)...)
+		lines = append(lines, body...)
+	}
+
+	for _, e := range m.entries {
+		tablePath := append(append([]string{}, path...), e.key)
+
+		child := w
+		child.CommentUndefined = w.CommentUndefined && !e.zero
+
+		var body []string
+
+		switch v := sampleEntryValue(e).(type) {
+		case *OrderedMap:
+			children, err := child.toml(v, tablePath)
+			if err != nil {
+				return nil, err
+			}
+
+			// the header of a table holding only tables is implied by theirs
+			if len(children) > 0 && children[0] == "" {
+				body = children[1:]
+			} else {
+				body = append([]string{"[" + tomlPath(tablePath) + "]"}, children...)
+			}
+
+		case []*OrderedMap:
+			for _, item := range v {
+				children, err := child.toml(item, tablePath)
+				if err != nil {
+					return nil, err
+				}
+
+				body = append(body, "[["+tomlPath(tablePath)+"]]")
+				body = append(body, children...)
+			}
+
+		default:
+			continue
+		}
+
+		if w.CommentUndefined && e.zero {
+			body = commentLines(body)
+		}
+
+		lines = append(lines, "")
+		lines = append(lines, descriptionLines(e.description)...)
+		lines = append(lines, body...)
+	}
+
+	return lines, nil
+}
+
+// sampleEntryValue returns the value of e to write: a *OrderedMap, a []*OrderedMap or a scalar.
+func sampleEntryValue(e mapEntry) interface{} {
+	if e.value == nil {
+		return ""
+	}
+	if items, ok := tables(e.value); ok {
+		return items
+	}
+	return e.value
+}
+
+func yamlScalar(value interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = key
+		if !tomlBareKey.MatchString(key) {
+			keys[i] = strconv.Quote(key)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+func descriptionLines(description string) []string {
+	if description == "" {
+		return nil
+	}
+
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		lines = append(lines, strings.TrimRight("# "+line, " "))
+	}
+	return lines
+}
+
+func indentLines(lines []string, indent string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if line != "" {
+			result[i] = indent + line
+		}
+	}
+	return result
+}
+
+// commentLines comments out the lines that are not already comments, at the indentation of the least indented line.
+func commentLines(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if text := strings.TrimLeft(line, " "); text != "" && (indent < 0 || len(line)-len(text) < indent) {
+			indent = len(line) - len(text)
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		if text == "" || strings.HasPrefix(text, "#") {
+			result[i] = line
+			continue
+		}
+		result[i] = line[:indent] + "# " + line[indent:]
+	}
+	return result
+}
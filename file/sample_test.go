@@ -0,0 +1,114 @@
+package file
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sampleServer struct {
+	Host string `description:"Host of the server."`
+	Port int    `description:"Port of the server."`
+}
+
+func (s *sampleServer) SetDefaults() {
+	s.Port = 8080
+}
+
+type sampleNotif struct {
+	Endpoint string         `description:"Endpoint of the notifier."`
+	Timeout  types.Duration `description:"Timeout of the requests."`
+}
+
+func (n *sampleNotif) SetDefaults() {
+	n.Timeout = types.Duration(10 * time.Second)
+}
+
+type sampleConfig struct {
+	Name    string                  `description:"Name of the application."`
+	Debug   bool                    `description:"Enable the debug mode."`
+	Ignored string                  `file:"-"`
+	Sources []string                `description:"List of sources.\nOne per line."`
+	Server  *sampleServer           `description:"Server settings."`
+	Mirrors []sampleServer          `description:"Mirrors."`
+	Notif   map[string]*sampleNotif `description:"Notifiers by name."`
+	Labels  map[string]string       `description:"Labels."`
+	Raw     map[string]interface{}  `description:"Raw settings."`
+}
+
+func (c *sampleConfig) SetDefaults() {
+	c.Name = "myapp"
+}
+
+func TestGenerateSample(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		extension string
+		opts      SampleOpts
+		expected  string
+	}{
+		{
+			desc:      "yaml",
+			extension: ".yml",
+			expected:  "fixtures/generated_sample.yml",
+		},
+		{
+			desc:      "yaml with undefined keys commented out",
+			extension: ".yaml",
+			opts:      SampleOpts{CommentUndefined: true},
+			expected:  "fixtures/generated_sample_commented.yml",
+		},
+		{
+			desc:      "toml",
+			extension: ".toml",
+			expected:  "fixtures/generated_sample.toml",
+		},
+		{
+			desc:      "toml with undefined keys commented out",
+			extension: ".toml",
+			opts:      SampleOpts{CommentUndefined: true},
+			expected:  "fixtures/generated_sample_commented.toml",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			content, err := GenerateSampleWithOpts(&sampleConfig{}, test.extension, test.opts)
+			require.NoError(t, err)
+
+			expected, err := os.ReadFile(test.expected)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(content))
+
+			element := &sampleConfig{}
+			err = Decode(test.expected, element)
+			require.NoError(t, err)
+
+			assert.Equal(t, "myapp", element.Name)
+			assert.Equal(t, 8080, element.Server.Port)
+			assert.Equal(t, types.Duration(10*time.Second), element.Notif["<name>"].Timeout)
+		})
+	}
+}
+
+func TestGenerateSample_errors(t *testing.T) {
+	_, err := GenerateSample(sampleConfig{}, ".yml")
+	require.EqualError(t, err, "unsupported element type: file.sampleConfig")
+
+	_, err = GenerateSample(&sampleConfig{}, ".json")
+	require.EqualError(t, err, "unsupported file extension: .json")
+}
+
+func TestGenerateSample_doesNotModifyElement(t *testing.T) {
+	element := &sampleConfig{}
+
+	_, err := GenerateSample(element, ".yml")
+	require.NoError(t, err)
+
+	assert.Equal(t, &sampleConfig{}, element)
+}
@@ -8,7 +8,9 @@ import (
 
 // Finder holds a list of file paths.
 type Finder struct {
-	BasePaths  []string
+	BasePaths []string
+	// Extensions appended to the base paths, without the leading dot, e.g. "yml" or "hcl".
+	// The supported ones are listed by file.SupportedExtensions.
 	Extensions []string
 }
 
@@ -34,6 +36,39 @@ func (f Finder) Find(configFile string) (string, error) {
 	return "", nil
 }
 
+// FindAll returns all the valid existing files among configFile
+// and the paths already registered with Finder, in the order Find considers them.
+func (f Finder) FindAll(configFile string) ([]string, error) {
+	var filePaths []string
+	seen := make(map[string]struct{})
+
+	for _, filePath := range f.getPaths(configFile) {
+		fp := os.ExpandEnv(filePath)
+
+		_, err := os.Stat(fp)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		absPath, err := filepath.Abs(fp)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[absPath]; ok {
+			continue
+		}
+		seen[absPath] = struct{}{}
+
+		filePaths = append(filePaths, absPath)
+	}
+
+	return filePaths, nil
+}
+
 func (f Finder) getPaths(configFile string) []string {
 	var paths []string
 	if strings.TrimSpace(configFile) != "" {
@@ -101,6 +101,50 @@ func TestFinder_Find(t *testing.T) {
 	}
 }
 
+func TestFinder_FindAll(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"system.toml", "user.yml", "project.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	finder := Finder{
+		BasePaths:  []string{filepath.Join(dir, "project"), "/my/path/gonfig", filepath.Join(dir, "user"), filepath.Join(dir, "system")},
+		Extensions: []string{"toml", "yaml", "yml"},
+	}
+
+	paths, err := finder.FindAll(filepath.Join(dir, "user.yml"))
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(dir, "user.yml"),
+		filepath.Join(dir, "project.yaml"),
+		filepath.Join(dir, "system.toml"),
+	}
+	assert.Equal(t, expected, paths)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fleet.hcl"), nil, 0o644))
+
+	paths, err = Finder{BasePaths: []string{filepath.Join(dir, "fleet")}, Extensions: []string{"yml", "hcl"}}.FindAll("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "fleet.hcl")}, paths)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "editor.json5"), nil, 0o644))
+
+	paths, err = Finder{BasePaths: []string{filepath.Join(dir, "editor")}, Extensions: []string{"json5"}}.FindAll("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "editor.json5")}, paths)
+
+	// no extensions
+	paths, err = Finder{BasePaths: []string{filepath.Join(dir, "fleet")}}.FindAll("")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	paths, err = Finder{BasePaths: []string{"/my/path/gonfig"}, Extensions: []string{"yml"}}.FindAll("")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
 func TestFinder_getPaths(t *testing.T) {
 	testCases := []struct {
 		desc       string
@@ -0,0 +1,210 @@
+package flag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// Alias holds the names of a flag renamed or aliased with the flag tag.
+type Alias struct {
+	// Name is the long name of the flag, e.g. --host.
+	Name string
+	// Short is the one-letter alias of the flag, e.g. -H, if any.
+	Short string
+}
+
+// String returns the names of the flag, e.g. "-H, --host".
+func (a Alias) String() string {
+	if a.Short == "" {
+		return a.Name
+	}
+	return a.Short + ", " + a.Name
+}
+
+// Aliases returns the names of the flags of element defined with the flag tag,
+// by flag name of their field (e.g. "server.host").
+// It returns an error if a tag is invalid or a name is used twice,
+// and no alias if element is not a pointer.
+func Aliases(element interface{}) (map[string]Alias, error) {
+	aliases, err := getFlagAliases(element)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Alias, len(aliases.byName))
+	for fieldName, alias := range aliases.byName {
+		result[fieldName] = alias.export(fieldName)
+	}
+
+	return result, nil
+}
+
+// flagAliases holds the flag names defined with the flag tag.
+type flagAliases struct {
+	// long maps the lower case custom names to the flag names of the fields.
+	long map[string]string
+	// short maps the one-letter aliases to the flag names of the fields.
+	short map[string]string
+	// byName maps the flag names of the fields to their custom name and alias.
+	byName map[string]flagAlias
+}
+
+type flagAlias struct {
+	name  string
+	short string
+}
+
+// getFlagAliases returns the flag names defined with the flag tag in element,
+// or an error if a tag is invalid or a name is used twice.
+func getFlagAliases(element interface{}) (flagAliases, error) {
+	a := flagAliases{
+		long:   map[string]string{},
+		short:  map[string]string{},
+		byName: map[string]flagAlias{},
+	}
+
+	if element == nil || reflect.TypeOf(element).Kind() != reflect.Pointer {
+		return a, nil
+	}
+
+	typ := reflect.TypeOf(element).Elem()
+
+	return a, a.add(getTypeHints(element), "", typ, false)
+}
+
+// add adds the aliases of the fields of typ, named after name.
+// nested reports whether typ is an item of a map or a slice, which can't have aliases.
+func (a flagAliases) add(fieldNames map[string]string, name string, typ reflect.Type, nested bool) error {
+	switch typ.Kind() {
+	case reflect.Map:
+		return a.add(fieldNames, getName(name, parser.MapNamePlaceholder), typ.Elem(), true)
+
+	case reflect.Slice:
+		return a.add(fieldNames, name+"[0]", typ.Elem(), true)
+
+	case reflect.Pointer:
+		return a.add(fieldNames, name, typ.Elem(), nested)
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+
+			if !parser.IsExported(field) {
+				continue
+			}
+
+			if field.Anonymous {
+				if err := a.add(fieldNames, name, field.Type, nested); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fieldName := getName(name, field.Name)
+
+			if tag, ok := field.Tag.Lookup(parser.TagFlag); ok {
+				if nested {
+					return fmt.Errorf("flag tag of %s: aliases are not supported in maps and slices", fieldName)
+				}
+
+				alias, err := parseFlagTag(tag)
+				if err != nil {
+					return fmt.Errorf("flag tag of %s: %w", fieldName, err)
+				}
+
+				if err = a.register(fieldNames, fieldName, alias); err != nil {
+					return err
+				}
+			}
+
+			if err := a.add(fieldNames, fieldName, field.Type, nested); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a flagAliases) register(fieldNames map[string]string, fieldName string, alias flagAlias) error {
+	if alias.name != "" {
+		name := strings.ToLower(alias.name)
+
+		if other, ok := a.long[name]; ok {
+			return fmt.Errorf("flag name %q of %s is already used by %s", alias.name, fieldName, other)
+		}
+		if _, ok := fieldNames[name]; ok && name != fieldName {
+			return fmt.Errorf("flag name %q of %s is already used by the field %s", alias.name, fieldName, name)
+		}
+
+		a.long[name] = fieldName
+	}
+
+	if alias.short != "" {
+		if other, ok := a.short[alias.short]; ok {
+			return fmt.Errorf("flag alias %q of %s is already used by %s", alias.short, fieldName, other)
+		}
+
+		a.short[alias.short] = fieldName
+	}
+
+	a.byName[fieldName] = alias
+
+	return nil
+}
+
+func (a flagAlias) export(fieldName string) Alias {
+	alias := Alias{Name: "--" + fieldName}
+	if a.name != "" {
+		alias.Name = "--" + a.name
+	}
+	if a.short != "" {
+		alias.Short = "-" + a.short
+	}
+	return alias
+}
+
+// parseFlagTag parses a flag tag such as "host,short=H".
+func parseFlagTag(tag string) (flagAlias, error) {
+	parts := strings.Split(tag, ",")
+
+	alias := flagAlias{name: strings.TrimSpace(parts[0])}
+	if strings.HasPrefix(alias.name, "-") || strings.ContainsAny(alias.name, "= ") {
+		return flagAlias{}, fmt.Errorf("invalid flag name %q", alias.name)
+	}
+
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+
+		switch key {
+		case "short":
+			if len(value) != 1 || !isLetter(value[0]) {
+				return flagAlias{}, fmt.Errorf("invalid flag alias %q: must be one letter", value)
+			}
+			alias.short = value
+		default:
+			return flagAlias{}, fmt.Errorf("unknown option %q", part)
+		}
+	}
+
+	return alias, nil
+}
+
+// resolve returns the flag name of the field matching the flag name, set with numMinuses minuses.
+func (a flagAliases) resolve(name string, numMinuses int) (string, bool) {
+	if numMinuses == 1 {
+		if fieldName, ok := a.short[name]; ok {
+			return fieldName, true
+		}
+	}
+
+	fieldName, ok := a.long[strings.ToLower(name)]
+	return fieldName, ok
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,228 @@
+package flag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type aliasServer struct {
+	Host string `flag:"host,short=H"`
+	Port int    `flag:",short=p"`
+}
+
+type aliasConfig struct {
+	Verbose bool `flag:",short=v"`
+	Quiet   bool `flag:"silent,short=q"`
+	Name    string
+	Server  *aliasServer
+}
+
+func TestParse_aliases(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		expected map[string]string
+	}{
+		{
+			desc:     "custom name",
+			args:     []string{"--host=localhost"},
+			expected: map[string]string{"gonfig.server.host": "localhost"},
+		},
+		{
+			desc:     "custom name case-insensitive",
+			args:     []string{"--Silent"},
+			expected: map[string]string{"gonfig.quiet": "true"},
+		},
+		{
+			desc:     "field path",
+			args:     []string{"--server.host", "localhost"},
+			expected: map[string]string{"gonfig.server.host": "localhost"},
+		},
+		{
+			desc:     "short with value",
+			args:     []string{"-H", "localhost", "-p=80"},
+			expected: map[string]string{"gonfig.server.host": "localhost", "gonfig.server.port": "80"},
+		},
+		{
+			desc:     "short bool",
+			args:     []string{"-v", "--name", "foo"},
+			expected: map[string]string{"gonfig.verbose": "true", "gonfig.name": "foo"},
+		},
+		{
+			desc:     "combined short bools",
+			args:     []string{"-vq"},
+			expected: map[string]string{"gonfig.verbose": "true", "gonfig.quiet": "true"},
+		},
+		{
+			desc:     "short is case-sensitive",
+			args:     []string{"-h"},
+			expected: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			fl, err := Parse(test.args, &aliasConfig{})
+			if test.expected == nil {
+				require.ErrorIs(t, err, ErrHelp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, fl)
+		})
+	}
+}
+
+func TestParse_combinedShortNotBool(t *testing.T) {
+	_, err := Parse([]string{"-vH"}, &aliasConfig{})
+	require.EqualError(t, err, "flag needs an argument: -vH")
+}
+
+func TestDecode_aliases(t *testing.T) {
+	element := &aliasConfig{}
+	err := Decode([]string{"-vH", "localhost"}, element)
+	require.Error(t, err)
+
+	err = Decode([]string{"-vq", "-H", "localhost", "-p", "8080"}, element)
+	require.NoError(t, err)
+
+	expected := &aliasConfig{
+		Verbose: true,
+		Quiet:   true,
+		Server:  &aliasServer{Host: "localhost", Port: 8080},
+	}
+	assert.Equal(t, expected, element)
+}
+
+func Test_getFlagAliases_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		element  interface{}
+		expected string
+	}{
+		{
+			desc: "duplicate alias",
+			element: &struct {
+				Foo bool `flag:",short=f"`
+				Bar bool `flag:",short=f"`
+			}{},
+			expected: `flag alias "f" of bar is already used by foo`,
+		},
+		{
+			desc: "duplicate name",
+			element: &struct {
+				Foo bool `flag:"baz"`
+				Bar bool `flag:"Baz"`
+			}{},
+			expected: `flag name "Baz" of bar is already used by foo`,
+		},
+		{
+			desc: "name of another field",
+			element: &struct {
+				Foo bool `flag:"bar"`
+				Bar bool
+			}{},
+			expected: `flag name "bar" of foo is already used by the field bar`,
+		},
+		{
+			desc: "invalid alias",
+			element: &struct {
+				Foo bool `flag:",short=fo"`
+			}{},
+			expected: `flag tag of foo: invalid flag alias "fo": must be one letter`,
+		},
+		{
+			desc: "invalid name",
+			element: &struct {
+				Foo bool `flag:"--foo"`
+			}{},
+			expected: `flag tag of foo: invalid flag name "--foo"`,
+		},
+		{
+			desc: "unknown option",
+			element: &struct {
+				Foo bool `flag:"foo,long=bar"`
+			}{},
+			expected: `flag tag of foo: unknown option "long=bar"`,
+		},
+		{
+			desc: "in a map",
+			element: &struct {
+				Foo map[string]aliasServer
+			}{},
+			expected: "flag tag of foo.<name>.host: aliases are not supported in maps and slices",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := Parse(nil, test.element)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestUsage_aliases(t *testing.T) {
+	var buf bytes.Buffer
+	err := Usage(&aliasConfig{}, UsageOpts{Output: &buf, Name: "myapp", HideEnv: true})
+	require.NoError(t, err)
+
+	expected := `Usage: myapp [flags]
+
+Flags:
+  -h, --help             Show this help.
+  --name string
+  -q, --silent bool
+  -v, --verbose bool
+
+server:
+  -H, --host string
+  -p, --server.port int
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestAliases(t *testing.T) {
+	aliases, err := Aliases(&aliasConfig{})
+	require.NoError(t, err)
+
+	expected := map[string]Alias{
+		"verbose":     {Name: "--verbose", Short: "-v"},
+		"quiet":       {Name: "--silent", Short: "-q"},
+		"server.host": {Name: "--host", Short: "-H"},
+		"server.port": {Name: "--server.port", Short: "-p"},
+	}
+	assert.Equal(t, expected, aliases)
+
+	assert.Equal(t, "-q, --silent", aliases["quiet"].String())
+	assert.Equal(t, "--name", Alias{Name: "--name"}.String())
+}
+
+func TestDecodeWithOpts_aliasesOnFill(t *testing.T) {
+	names := map[string]string{}
+	opts := DecodeOpts{OnFill: func(path, name string) {
+		names[path] = name
+	}}
+
+	err := DecodeWithOpts([]string{"-vq", "-H", "localhost", "--server.port=80", "--Name", "foo"}, &aliasConfig{}, opts)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"verbose":     "-v",
+		"quiet":       "-q",
+		"server.host": "-H",
+		"server.port": "--server.port",
+		"name":        "--Name",
+	}
+	assert.Equal(t, expected, names)
+}
+
+func TestAliases_structValue(t *testing.T) {
+	aliases, err := Aliases(aliasConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+}
@@ -2,6 +2,8 @@
 package flag
 
 import (
+	"strings"
+
 	"github.com/crazy-max/gonfig/parser"
 )
 
@@ -12,12 +14,46 @@ import (
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func Decode(args []string, element interface{}) error {
-	ref, err := Parse(args, element)
+	return DecodeWithOpts(args, element, DecodeOpts{})
+}
+
+// DecodeOpts holds options used when decoding flag arguments.
+type DecodeOpts struct {
+	// OnFill is called for each leaf value set into the element,
+	// with its dotted path and the flag it comes from, as set in the arguments (e.g. -H or --host).
+	OnFill func(path, name string)
+	// OnArgs is called with the remaining arguments: the ones following the first non-flag argument
+	// or the -- terminator.
+	OnArgs func(args []string)
+}
+
+// DecodeWithOpts decodes the given flag arguments into the given element using opts.
+func DecodeWithOpts(args []string, element interface{}, opts DecodeOpts) error {
+	f, err := parse(args, element)
 	if err != nil {
 		return err
 	}
 
-	return parser.Decode(ref, element, parser.DefaultRootName)
+	var decodeOpts parser.DecodeOpts
+	if opts.OnFill != nil {
+		decodeOpts.OnFill = func(path, key string) {
+			name, ok := f.flags[strings.ToLower(key)]
+			if !ok {
+				name = "--" + key
+			}
+			opts.OnFill(path, name)
+		}
+	}
+
+	if err = parser.DecodeWithOpts(f.values, element, parser.DefaultRootName, decodeOpts); err != nil {
+		return err
+	}
+
+	if opts.OnArgs != nil {
+		opts.OnArgs(f.args)
+	}
+
+	return nil
 }
 
 // Encode encodes the configuration in element into the flags represented in the returned Flats.
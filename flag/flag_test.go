@@ -441,6 +441,31 @@ func TestEncode(t *testing.T) {
 		element  interface{}
 		expected []parser.Flat
 	}{
+		{
+			desc: "default tag",
+			element: &struct {
+				Field  string `description:"field description" default:"foo"`
+				Field2 int    `default:"42"`
+				Field3 int    `default:"42"`
+			}{
+				Field3: 7,
+			},
+			expected: []parser.Flat{
+				{
+					Name:        "field",
+					Description: "field description",
+					Default:     "foo",
+				},
+				{
+					Name:    "field2",
+					Default: "42",
+				},
+				{
+					Name:    "field3",
+					Default: "7",
+				},
+			},
+		},
 		{
 			desc: "string field",
 			element: &struct {
@@ -963,3 +988,20 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeWithOpts_onArgs(t *testing.T) {
+	element := &struct {
+		Foo string
+	}{}
+
+	var remaining []string
+	err := DecodeWithOpts([]string{"--foo=bar", "serve", "--port=80"}, element, DecodeOpts{
+		OnArgs: func(args []string) {
+			remaining = args
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "bar", element.Foo)
+	assert.Equal(t, []string{"serve", "--port=80"}, remaining)
+}
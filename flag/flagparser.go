@@ -12,12 +12,39 @@ import (
 // Parse parses the command-line flag arguments into a map,
 // using the type information in element to discriminate whether a flag is supposed to be a bool,
 // and other such ambiguities.
+// The flags can also be set with the names and the one-letter aliases defined with the flag tag,
+// and several one-letter aliases of bool flags can be combined (e.g. -vq).
+// It returns ErrHelp if the -h or --help flag is set and is not a flag of element.
 func Parse(args []string, element interface{}) (map[string]string, error) {
-	f := flagSet{
+	values, _, err := ParseArgs(args, element)
+	return values, err
+}
+
+// ParseArgs parses the command-line flag arguments like Parse,
+// and also returns the remaining arguments: the ones following the first non-flag argument or the -- terminator.
+func ParseArgs(args []string, element interface{}) (map[string]string, []string, error) {
+	f, err := parse(args, element)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f.values, f.args, nil
+}
+
+// parse parses the command-line flag arguments into a flagSet.
+func parse(args []string, element interface{}) (*flagSet, error) {
+	aliases, err := getFlagAliases(element)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &flagSet{
 		flagTypes: getFlagTypes(element),
+		typeHints: getTypeHints(element),
+		aliases:   aliases,
 		args:      args,
 		values:    make(map[string]string),
 		keys:      make(map[string]string),
+		flags:     make(map[string]string),
 	}
 
 	for {
@@ -30,14 +57,18 @@ func Parse(args []string, element interface{}) (map[string]string, error) {
 		}
 		return nil, err
 	}
-	return f.values, nil
+	return f, nil
 }
 
 type flagSet struct {
 	flagTypes map[string]reflect.Kind
+	typeHints map[string]string
+	aliases   flagAliases
 	args      []string
 	values    map[string]string
 	keys      map[string]string
+	// flags holds the flag as set in the arguments (e.g. -H), by lower case flag name of its field.
+	flags map[string]string
 }
 
 func (f *flagSet) parseOne() (bool, error) {
@@ -76,14 +107,27 @@ func (f *flagSet) parseOne() (bool, error) {
 		}
 	}
 
+	flag := s[:numMinuses] + name
+
+	if fieldName, ok := f.aliases.resolve(name, numMinuses); ok {
+		name = fieldName
+	} else if (name == "h" || name == "help") && f.typeHints[name] == "" {
+		return false, ErrHelp
+	} else if numMinuses == 1 && !hasValue && f.isShortGroup(name) {
+		for i := range name {
+			f.setValue(f.aliases.short[name[i:i+1]], "true", "-"+name[i:i+1])
+		}
+		return true, nil
+	}
+
 	if hasValue {
-		f.setValue(name, value)
+		f.setValue(name, value, flag)
 		return true, nil
 	}
 
 	flagType := f.getFlagType(name)
 	if flagType == reflect.Bool || flagType == reflect.Pointer {
-		f.setValue(name, "true")
+		f.setValue(name, "true", flag)
 		return true, nil
 	}
 
@@ -97,14 +141,37 @@ func (f *flagSet) parseOne() (bool, error) {
 		return false, fmt.Errorf("flag needs an argument: -%s", name)
 	}
 
-	f.setValue(name, value)
+	f.setValue(name, value, flag)
 	return true, nil
 }
 
-func (f *flagSet) setValue(name, value string) {
+// isShortGroup reports whether name is made of one-letter aliases of bool flags (e.g. -vq).
+func (f *flagSet) isShortGroup(name string) bool {
+	if len(name) < 2 || f.typeHints[strings.ToLower(name)] != "" {
+		return false
+	}
+
+	for i := range name {
+		fieldName, ok := f.aliases.short[name[i:i+1]]
+		if !ok {
+			return false
+		}
+
+		if flagType := f.getFlagType(fieldName); flagType != reflect.Bool && flagType != reflect.Pointer {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setValue sets the value of the flag name of a field, set in the arguments as flag.
+func (f *flagSet) setValue(name, value, flag string) {
 	srcKey := parser.DefaultRootName + "." + name
 	neutralKey := strings.ToLower(srcKey)
 
+	f.flags[strings.ToLower(name)] = flag
+
 	key, ok := f.keys[neutralKey]
 	if !ok {
 		f.keys[neutralKey] = srcKey
@@ -384,3 +384,51 @@ func TestParse_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArgs(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		args              []string
+		expected          map[string]string
+		expectedRemaining []string
+	}{
+		{
+			desc:              "no remaining args",
+			args:              []string{"--foo=bar"},
+			expected:          map[string]string{"gonfig.foo": "bar"},
+			expectedRemaining: []string{},
+		},
+		{
+			desc:              "positional args",
+			args:              []string{"--foo=bar", "a.txt", "--foo=baz", "b.txt"},
+			expected:          map[string]string{"gonfig.foo": "bar"},
+			expectedRemaining: []string{"a.txt", "--foo=baz", "b.txt"},
+		},
+		{
+			desc:              "terminator",
+			args:              []string{"--foo=bar", "--", "--foo=baz"},
+			expected:          map[string]string{"gonfig.foo": "bar"},
+			expectedRemaining: []string{"--foo=baz"},
+		},
+		{
+			desc:              "single dash",
+			args:              []string{"-", "--foo=bar"},
+			expected:          map[string]string{},
+			expectedRemaining: []string{"-", "--foo=bar"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			element := &struct {
+				Foo string
+			}{}
+
+			values, remaining, err := ParseArgs(test.args, element)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, values)
+			assert.Equal(t, test.expectedRemaining, remaining)
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package flag
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/gonfig/env"
+	"github.com/crazy-max/gonfig/generator"
+	"github.com/crazy-max/gonfig/parser"
+	"github.com/crazy-max/gonfig/types"
+)
+
+// ErrHelp is returned by Decode when the -h or --help flag is set.
+var ErrHelp = errors.New("flag: help requested")
+
+// UsageOpts holds options used when writing the usage.
+type UsageOpts struct {
+	// Output is where the usage is written. Default to os.Stderr.
+	Output io.Writer
+	// Name of the command. Default to the base name of os.Args[0].
+	Name string
+	// EnvPrefix is the prefix of the environment variables shown next to the flags. Default to "GONFIG_".
+	EnvPrefix string
+	// HideEnv hides the environment variables.
+	HideEnv bool
+	// Commands are the subcommands listed after the usage line.
+	Commands []Command
+}
+
+// Command describes a subcommand in the usage.
+type Command struct {
+	Name        string
+	Description string
+}
+
+// Usage writes the usage of the flags of element, grouped by parent struct,
+// with their type, their environment variable, their description and their default value.
+// element is only used for its type: the defaults are the ones set by generator.Generate.
+func Usage(element interface{}, opts UsageOpts) error {
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+	if opts.Name == "" {
+		opts.Name = filepath.Base(os.Args[0])
+	}
+	if opts.EnvPrefix == "" {
+		opts.EnvPrefix = env.DefaultNamePrefix
+	}
+
+	rType := reflect.TypeOf(element)
+	if rType == nil || rType.Kind() != reflect.Pointer || rType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported element type: %T", element)
+	}
+
+	sample := reflect.New(rType.Elem()).Interface()
+	generator.Generate(sample)
+
+	flats, err := Encode(sample)
+	if err != nil {
+		return err
+	}
+
+	envNames := make(map[string]string)
+	if !opts.HideEnv {
+		envFlats, err := env.Encode(opts.EnvPrefix, sample)
+		if err != nil {
+			return err
+		}
+		for _, flat := range envFlats {
+			envNames[strings.TrimPrefix(flat.Name, opts.EnvPrefix)] = flat.Name
+		}
+	}
+
+	hints := getTypeHints(sample)
+
+	aliases, err := getFlagAliases(sample)
+	if err != nil {
+		return err
+	}
+
+	groups := map[string][]usageLine{}
+	if help := helpFlags(hints, aliases); help != "" {
+		groups[""] = []usageLine{{flag: help, description: "Show this help."}}
+	}
+
+	for _, flat := range flats {
+		line := usageLine{
+			flag:        "--" + flat.Name,
+			description: strings.ReplaceAll(flat.Description, "\n", " "),
+		}
+
+		if alias, ok := aliases.byName[flat.Name]; ok {
+			line.flag = alias.export(flat.Name).String()
+		}
+
+		if hint := hints[flat.Name]; hint != "" {
+			line.flag += " " + hint
+		}
+
+		if envName, ok := envNames[strings.ToUpper(strings.ReplaceAll(flat.Name, ".", "_"))]; ok {
+			line.env = "$" + envName
+		}
+
+		if !isZeroDefault(flat.Default) {
+			value := flat.Default
+			if _, err := strconv.Atoi(value); err == nil && hints[flat.Name] == "duration" {
+				// suffix-less durations are in seconds
+				value += "s"
+			}
+			line.description = strings.TrimSpace(line.description + " (default: " + value + ")")
+		}
+
+		group := groupName(flats, flat.Name)
+		groups[group] = append(groups[group], line)
+	}
+
+	return writeUsage(opts, groups)
+}
+
+// helpFlags returns the flags showing the help that are not used by element.
+func helpFlags(hints map[string]string, aliases flagAliases) string {
+	var names []string
+	if _, ok := aliases.resolve("h", 1); !ok && hints["h"] == "" {
+		names = append(names, "-h")
+	}
+	if _, ok := aliases.resolve("help", 2); !ok && hints["help"] == "" {
+		names = append(names, "--help")
+	}
+	return strings.Join(names, ", ")
+}
+
+type usageLine struct {
+	flag        string
+	env         string
+	description string
+}
+
+func writeUsage(opts UsageOpts, groups map[string][]usageLine) error {
+	names := make([]string, 0, len(groups))
+	flagWidth, envWidth := 0, 0
+	for name, lines := range groups {
+		names = append(names, name)
+		for _, line := range lines {
+			flagWidth = max(flagWidth, len(line.flag))
+			envWidth = max(envWidth, len(line.env))
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	if len(opts.Commands) == 0 {
+		_, _ = fmt.Fprintf(&b, "Usage: %s [flags]\n", opts.Name)
+	} else {
+		_, _ = fmt.Fprintf(&b, "Usage: %s [flags] <command> [command flags]\n", opts.Name)
+
+		nameWidth := 0
+		for _, command := range opts.Commands {
+			nameWidth = max(nameWidth, len(command.Name))
+		}
+
+		b.WriteString("\nCommands:\n")
+		for _, command := range opts.Commands {
+			b.WriteString(strings.TrimRight(fmt.Sprintf("  %-*s  %s", nameWidth, command.Name, command.Description), " ") + "\n")
+		}
+	}
+
+	for _, name := range names {
+		if name == "" {
+			b.WriteString("\nFlags:\n")
+		} else {
+			_, _ = fmt.Fprintf(&b, "\n%s:\n", name)
+		}
+
+		for _, line := range groups[name] {
+			text := fmt.Sprintf("  %-*s", flagWidth, line.flag)
+			if envWidth > 0 {
+				text += fmt.Sprintf("  %-*s", envWidth, line.env)
+			}
+			b.WriteString(strings.TrimRight(text+"  "+line.description, " ") + "\n")
+		}
+	}
+
+	_, err := io.WriteString(opts.Output, b.String())
+	return err
+}
+
+// groupName returns the name of the group of the flag name:
+// its parent, or the flag itself if it enables a struct whose fields are flags too.
+func groupName(flats []parser.Flat, name string) string {
+	for _, flat := range flats {
+		if strings.HasPrefix(flat.Name, name+".") {
+			return name
+		}
+	}
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+
+	return ""
+}
+
+func isZeroDefault(value string) bool {
+	switch value {
+	case "", "false", "0", "0s":
+		return true
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	return err == nil && f == 0
+}
+
+// getTypeHints returns the type hints of the flags of element, such as string, duration or list.
+func getTypeHints(element interface{}) map[string]string {
+	hints := map[string]string{}
+
+	if element == nil || reflect.TypeOf(element).Kind() != reflect.Pointer {
+		return hints
+	}
+
+	addTypeHints(hints, "", reflect.TypeOf(element).Elem())
+
+	return hints
+}
+
+// TypeHint returns the type hint of a scalar type: bool, string, int, duration, uint or float.
+// It returns an empty string for the other types.
+func TypeHint(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch typ {
+		case reflect.TypeOf(types.Duration(0)), reflect.TypeOf(time.Duration(0)):
+			return "duration"
+		}
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	default:
+		return ""
+	}
+}
+
+// addTypeHints adds to hints the type hint of the flags defined by typ, named after their flag name.
+func addTypeHints(hints map[string]string, name string, typ reflect.Type) {
+	if hint := TypeHint(typ); hint != "" {
+		hints[name] = hint
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice:
+		hints[name] = "list"
+		addTypeHints(hints, name+"[0]", typ.Elem())
+
+	case reflect.Map:
+		key := getName(name, parser.MapNamePlaceholder)
+		addTypeHints(hints, key, typ.Elem())
+		hints[key] = "map"
+
+	case reflect.Pointer:
+		addTypeHints(hints, name, typ.Elem())
+		if typ.Elem().Kind() == reflect.Struct {
+			hints[name] = "bool"
+		}
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+
+			if !parser.IsExported(field) {
+				continue
+			}
+
+			if field.Anonymous {
+				addTypeHints(hints, name, field.Type)
+			} else {
+				addTypeHints(hints, getName(name, field.Name), field.Type)
+			}
+		}
+
+	default:
+		// noop
+	}
+}
@@ -0,0 +1,180 @@
+package flag
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type usageServer struct {
+	Host string `description:"Host of the server."`
+	Port int    `description:"Port of the server."`
+}
+
+func (s *usageServer) SetDefaults() {
+	s.Port = 8080
+}
+
+type usageConfig struct {
+	Name    string                  `description:"Name of the application."`
+	Debug   bool                    `description:"Enable the debug mode."`
+	Timeout types.Duration          `description:"Timeout of the requests."`
+	Sources []string                `description:"List of sources."`
+	Ratio   float64                 `description:"Sampling ratio."`
+	Server  *usageServer            `description:"Server settings."`
+	Notif   map[string]*usageServer `description:"Notifiers by name."`
+	Labels  map[string]string       `description:"Labels."`
+}
+
+func (c *usageConfig) SetDefaults() {
+	c.Name = "myapp"
+	c.Timeout = types.Duration(10 * time.Second)
+}
+
+func TestUsage(t *testing.T) {
+	var buf bytes.Buffer
+	err := Usage(&usageConfig{}, UsageOpts{Output: &buf, Name: "myapp", EnvPrefix: "MYAPP_"})
+	require.NoError(t, err)
+
+	expected := `Usage: myapp [flags]
+
+Flags:
+  -h, --help                                            Show this help.
+  --debug bool                $MYAPP_DEBUG              Enable the debug mode.
+  --name string               $MYAPP_NAME               Name of the application. (default: myapp)
+  --ratio float               $MYAPP_RATIO              Sampling ratio.
+  --sources list              $MYAPP_SOURCES            List of sources.
+  --timeout duration          $MYAPP_TIMEOUT            Timeout of the requests. (default: 10s)
+
+labels:
+  --labels.<name> map         $MYAPP_LABELS_<NAME>      Labels.
+
+notif.<name>:
+  --notif.<name> map          $MYAPP_NOTIF_<NAME>       Notifiers by name.
+  --notif.<name>.host string  $MYAPP_NOTIF_<NAME>_HOST  Host of the server.
+  --notif.<name>.port int     $MYAPP_NOTIF_<NAME>_PORT  Port of the server. (default: 8080)
+
+server:
+  --server.host string        $MYAPP_SERVER_HOST        Host of the server.
+  --server.port int           $MYAPP_SERVER_PORT        Port of the server. (default: 8080)
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestUsage_hideEnv(t *testing.T) {
+	var buf bytes.Buffer
+	err := Usage(&usageServer{}, UsageOpts{Output: &buf, Name: "server", HideEnv: true})
+	require.NoError(t, err)
+
+	expected := `Usage: server [flags]
+
+Flags:
+  -h, --help     Show this help.
+  --host string  Host of the server.
+  --port int     Port of the server. (default: 8080)
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestUsage_unsupportedElement(t *testing.T) {
+	err := Usage(usageConfig{}, UsageOpts{})
+	require.EqualError(t, err, "unsupported element type: flag.usageConfig")
+}
+
+func TestDecode_help(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []string
+		element  interface{}
+		expected error
+	}{
+		{
+			desc:     "short",
+			args:     []string{"--name=foo", "-h"},
+			element:  &usageConfig{},
+			expected: ErrHelp,
+		},
+		{
+			desc:     "long",
+			args:     []string{"--help"},
+			element:  &usageConfig{},
+			expected: ErrHelp,
+		},
+		{
+			desc:    "after the terminator",
+			args:    []string{"--", "--help"},
+			element: &usageConfig{},
+		},
+		{
+			desc: "field named help",
+			args: []string{"--help"},
+			element: &struct {
+				Help bool
+			}{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := Decode(test.args, test.element)
+			if test.expected != nil {
+				require.ErrorIs(t, err, test.expected)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUsage_commands(t *testing.T) {
+	var buf bytes.Buffer
+	err := Usage(&usageServer{}, UsageOpts{
+		Output:  &buf,
+		Name:    "myapp",
+		HideEnv: true,
+		Commands: []Command{
+			{Name: "serve", Description: "Start the server."},
+			{Name: "version"},
+		},
+	})
+	require.NoError(t, err)
+
+	expected := `Usage: myapp [flags] <command> [command flags]
+
+Commands:
+  serve    Start the server.
+  version
+
+Flags:
+  -h, --help     Show this help.
+  --host string  Host of the server.
+  --port int     Port of the server. (default: 8080)
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestTypeHint(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: true, expected: "bool"},
+		{value: "", expected: "string"},
+		{value: 0, expected: "int"},
+		{value: types.Duration(0), expected: "duration"},
+		{value: time.Duration(0), expected: "duration"},
+		{value: uint8(0), expected: "uint"},
+		{value: float32(0), expected: "float"},
+		{value: []string{}, expected: ""},
+		{value: usageServer{}, expected: ""},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, TypeHint(reflect.TypeOf(test.value)), "%T", test.value)
+	}
+}
@@ -58,6 +58,11 @@ func setPtr(field reflect.Value) {
 		field.Set(reflect.New(field.Type().Elem()))
 	}
 
+	if field.Elem().Kind() == reflect.Struct {
+		// invalid default values are reported when the element is filled
+		_ = parser.FillDefaults(field.Interface())
+	}
+
 	if field.Type().Implements(reflect.TypeOf((*initializer)(nil)).Elem()) {
 		method := field.MethodByName("SetDefaults")
 		if method.IsValid() {
@@ -437,3 +437,32 @@ type Yaa struct {
 	FieldIn13 *bool
 	FieldIn14 *int
 }
+
+type Yd struct {
+	Foo  string            `default:"foo"`
+	Bar  *int              `default:"42"`
+	Baz  map[string]string `default:"a=b"`
+	Sub  *Yds
+	Subs []Yds
+}
+
+type Yds struct {
+	Foo []string `default:"a,b"`
+}
+
+func TestGenerate_defaults(t *testing.T) {
+	element := &Yd{}
+	Generate(element)
+
+	expected := &Yd{
+		Foo: "foo",
+		Bar: func(v int) *int { return &v }(42),
+		Baz: map[string]string{
+			"a":                       "b",
+			parser.MapNamePlaceholder: "",
+		},
+		Sub:  &Yds{Foo: []string{"a", "b"}},
+		Subs: []Yds{{Foo: []string{"a", "b"}}},
+	}
+	assert.Equal(t, expected, element)
+}
@@ -1,7 +1,36 @@
 package gonfig
 
+import (
+	"github.com/crazy-max/gonfig/parser"
+	"github.com/pkg/errors"
+)
+
 // Loader is a configuration resource loader.
 type Loader interface {
 	// Load populates cfg.
 	Load(cfg interface{}) (bool, error)
 }
+
+// defaultsLoader is a Loader whose Load applies the default tags before loading,
+// and whose load only loads, once the defaults have been applied by a Chain.
+type defaultsLoader interface {
+	Loader
+	load(cfg interface{}) (bool, error)
+}
+
+// loadWithoutDefaults loads cfg with loader without applying the default tags again,
+// so that the zero values set by the previous loaders are kept.
+func loadWithoutDefaults(loader Loader, cfg interface{}) (bool, error) {
+	if l, ok := loader.(defaultsLoader); ok {
+		return l.load(cfg)
+	}
+	return loader.Load(cfg)
+}
+
+// fillDefaults sets the zero fields of cfg to the value of their default tag.
+func fillDefaults(cfg interface{}) error {
+	if err := parser.FillDefaults(cfg); err != nil {
+		return errors.Wrap(err, "Failed to set default values")
+	}
+	return nil
+}
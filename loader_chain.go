@@ -0,0 +1,213 @@
+package gonfig
+
+import (
+	"sort"
+
+	"github.com/crazy-max/gonfig/validator"
+)
+
+// Source identifies the kind of resource a Loader reads from.
+type Source string
+
+const (
+	// SourceFile is the source of a FileLoader.
+	SourceFile Source = "file"
+	// SourceDotEnv is the source of a DotEnvLoader.
+	SourceDotEnv Source = "dotenv"
+	// SourceEnv is the source of an EnvLoader.
+	SourceEnv Source = "env"
+	// SourceFlag is the source of a FlagLoader.
+	SourceFlag Source = "flag"
+)
+
+// DefaultPrecedence is the precedence used by a Chain when none is set:
+// files are overridden by .env files, overridden by environment variables, themselves overridden by flags.
+var DefaultPrecedence = []Source{SourceFile, SourceDotEnv, SourceEnv, SourceFlag}
+
+// SourceLoader is a Loader that knows the kind of resource it reads from.
+type SourceLoader interface {
+	Loader
+	// Source returns the kind of resource read by the loader.
+	Source() Source
+}
+
+// FilesLoader is a Loader that reads files.
+type FilesLoader interface {
+	Loader
+	// GetFilenames returns the files read by the last load, in the order they have been merged.
+	GetFilenames() []string
+}
+
+// EnvPrefixLoader is a Loader that reads environment variables.
+type EnvPrefixLoader interface {
+	Loader
+	// EnvPrefix returns the prefix of the environment variables read by the loader.
+	EnvPrefix() string
+}
+
+// Chain is the structure representing a loader applying several loaders in order.
+type Chain struct {
+	result Result
+	cfg    ChainConfig
+}
+
+// ChainConfig loads a configuration from several loaders.
+type ChainConfig struct {
+	// Loaders to apply.
+	Loaders []Loader
+	// Precedence of the sources from the lowest to the highest one.
+	// A loader whose source comes later overrides the values set by the previous ones.
+	// Loaders that are not a SourceLoader or whose source is not listed are applied first,
+	// in their declaration order. Default to DefaultPrecedence.
+	Precedence []Source
+	// OnLoad is called each time a loader has been applied successfully.
+	OnLoad func(res LoaderResult)
+	// Validate checks the loaded configuration against the rules of the validate struct tags.
+	// The errors name the environment variable to set if an EnvPrefixLoader, e.g. an EnvLoader,
+	// is part of the loaders.
+	Validate bool
+}
+
+// Result holds the outcome of each loader applied by a Chain.
+type Result struct {
+	Loaders []LoaderResult
+	// Provenance holds the origin of the values set by the loaders
+	// implementing ProvenanceLoader, following the precedence.
+	Provenance Provenance
+}
+
+// LoaderResult holds the outcome of a single loader.
+type LoaderResult struct {
+	Source Source
+	Loader Loader
+	Found  bool
+	// Files holds the files read by a FilesLoader, e.g. a FileLoader including the drop-in files,
+	// in the order they have been merged, or a DotEnvLoader.
+	Files []string
+}
+
+// Found returns true if at least one loader found a configuration.
+func (r Result) Found() bool {
+	for _, res := range r.Loaders {
+		if res.Found {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources returns the sources of the loaders that found a configuration.
+func (r Result) Sources() []Source {
+	var sources []Source
+	for _, res := range r.Loaders {
+		if res.Found {
+			sources = append(sources, res.Source)
+		}
+	}
+	return sources
+}
+
+// NewChain creates a new Loader from the ChainConfig cfg.
+func NewChain(cfg ChainConfig) *Chain {
+	return &Chain{
+		cfg: cfg,
+	}
+}
+
+// Load applies the given loaders to cfg using the default precedence
+// and returns the aggregated result.
+func Load(cfg interface{}, loaders ...Loader) (Result, error) {
+	chain := NewChain(ChainConfig{
+		Loaders: loaders,
+	})
+	_, err := chain.Load(cfg)
+	return chain.GetResult(), err
+}
+
+// GetResult returns the result of the last load.
+func (c *Chain) GetResult() Result {
+	return c.result
+}
+
+// Load loads the configuration from all the loaders following the precedence,
+// once the zero fields of cfg have been set to the value of their default tag.
+func (c *Chain) Load(cfg interface{}) (bool, error) {
+	if err := fillDefaults(cfg); err != nil {
+		return false, err
+	}
+	return c.load(cfg)
+}
+
+func (c *Chain) load(cfg interface{}) (bool, error) {
+	c.result = Result{Provenance: Provenance{}}
+
+	for _, loader := range c.sortedLoaders() {
+		found, err := loadWithoutDefaults(loader, cfg)
+		if err != nil {
+			return false, err
+		}
+
+		res := LoaderResult{
+			Source: getSource(loader),
+			Loader: loader,
+			Found:  found,
+		}
+		if l, ok := loader.(FilesLoader); ok {
+			res.Files = l.GetFilenames()
+		}
+		c.result.Loaders = append(c.result.Loaders, res)
+
+		if l, ok := loader.(ProvenanceLoader); ok {
+			c.result.Provenance.Merge(l.GetProvenance())
+		}
+
+		if c.cfg.OnLoad != nil {
+			c.cfg.OnLoad(res)
+		}
+	}
+
+	if c.cfg.Validate {
+		if err := validator.Validate(cfg, validator.Opts{EnvPrefix: c.envPrefix()}); err != nil {
+			return false, err
+		}
+	}
+
+	return c.result.Found(), nil
+}
+
+func (c *Chain) envPrefix() string {
+	for _, loader := range c.cfg.Loaders {
+		if l, ok := loader.(EnvPrefixLoader); ok {
+			return l.EnvPrefix()
+		}
+	}
+	return ""
+}
+
+func (c *Chain) sortedLoaders() []Loader {
+	precedence := c.cfg.Precedence
+	if len(precedence) == 0 {
+		precedence = DefaultPrecedence
+	}
+
+	ranks := make(map[Source]int, len(precedence))
+	for i, source := range precedence {
+		ranks[source] = i + 1
+	}
+
+	loaders := make([]Loader, len(c.cfg.Loaders))
+	copy(loaders, c.cfg.Loaders)
+
+	sort.SliceStable(loaders, func(i, j int) bool {
+		return ranks[getSource(loaders[i])] < ranks[getSource(loaders[j])]
+	})
+
+	return loaders
+}
+
+func getSource(loader Loader) Source {
+	if l, ok := loader.(SourceLoader); ok {
+		return l.Source()
+	}
+	return ""
+}
@@ -0,0 +1,255 @@
+package gonfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	example "github.com/crazy-max/gonfig/contrib/example/config"
+	"github.com/crazy-max/gonfig/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type errLoader struct{}
+
+func (errLoader) Load(_ interface{}) (bool, error) {
+	return false, errors.New("boom")
+}
+
+func TestChain(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		precedence []Source
+		cfgfile    string
+		environ    map[string]string
+		args       []string
+		found      bool
+		sources    []Source
+		expected   string
+	}{
+		{
+			desc:     "nothing found",
+			found:    false,
+			expected: "",
+		},
+		{
+			desc:     "file only",
+			cfgfile:  "./fixtures/config.test.yml",
+			found:    true,
+			sources:  []Source{SourceFile},
+			expected: "test.rebex.net",
+		},
+		{
+			desc:    "env overrides file",
+			cfgfile: "./fixtures/config.test.yml",
+			environ: map[string]string{
+				env.DefaultNamePrefix + "SERVER_FTP_HOST": "env.example.com",
+			},
+			found:    true,
+			sources:  []Source{SourceFile, SourceEnv},
+			expected: "env.example.com",
+		},
+		{
+			desc:    "flag overrides env and file",
+			cfgfile: "./fixtures/config.test.yml",
+			environ: map[string]string{
+				env.DefaultNamePrefix + "SERVER_FTP_HOST": "env.example.com",
+			},
+			args:     []string{"--server.ftp.host=flag.example.com"},
+			found:    true,
+			sources:  []Source{SourceFile, SourceEnv, SourceFlag},
+			expected: "flag.example.com",
+		},
+		{
+			desc:       "custom precedence",
+			precedence: []Source{SourceFlag, SourceEnv, SourceFile},
+			cfgfile:    "./fixtures/config.test.yml",
+			environ: map[string]string{
+				env.DefaultNamePrefix + "SERVER_FTP_HOST": "env.example.com",
+			},
+			args:     []string{"--server.ftp.host=flag.example.com"},
+			found:    true,
+			sources:  []Source{SourceFlag, SourceEnv, SourceFile},
+			expected: "test.rebex.net",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			for k, v := range tt.environ {
+				t.Setenv(k, v)
+			}
+
+			var loaded []Source
+			chain := NewChain(ChainConfig{
+				// declaration order must not matter
+				Loaders: []Loader{
+					NewFlagLoader(FlagLoaderConfig{Args: tt.args}),
+					NewEnvLoader(EnvLoaderConfig{Prefix: env.DefaultNamePrefix}),
+					NewFileLoader(FileLoaderConfig{Filename: tt.cfgfile}),
+				},
+				Precedence: tt.precedence,
+				OnLoad: func(res LoaderResult) {
+					loaded = append(loaded, res.Source)
+				},
+			})
+
+			cfg := &example.Config{}
+			found, err := chain.Load(cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.sources, chain.GetResult().Sources())
+			assert.Len(t, loaded, 3)
+
+			if tt.expected == "" {
+				assert.Nil(t, cfg.Server)
+				return
+			}
+			require.NotNil(t, cfg.Server)
+			assert.Equal(t, tt.expected, cfg.Server.FTP.Host)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cfg := &example.Config{}
+
+	res, err := Load(cfg,
+		NewFlagLoader(FlagLoaderConfig{Args: []string{"--logLevel=debug"}}),
+		NewFileLoader(FileLoaderConfig{Filename: "./fixtures/config.test.yml"}),
+	)
+	require.NoError(t, err)
+
+	assert.True(t, res.Found())
+	require.Len(t, res.Loaders, 2)
+	assert.Equal(t, SourceFile, res.Loaders[0].Source)
+	assert.Equal(t, SourceFlag, res.Loaders[1].Source)
+	assert.Equal(t, "debug", cfg.LogLevel)
+}
+
+type defaultsConfig struct {
+	Host string `default:"localhost"`
+	Port int    `default:"80"`
+}
+
+func TestLoad_defaults(t *testing.T) {
+	cfg := &defaultsConfig{}
+
+	_, err := Load(cfg, NewFlagLoader(FlagLoaderConfig{Args: []string{"--host=example.com"}}))
+	require.NoError(t, err)
+
+	assert.Equal(t, &defaultsConfig{Host: "example.com", Port: 80}, cfg)
+
+	// without any value found
+	cfg = &defaultsConfig{}
+
+	_, err = Load(cfg, NewFlagLoader(FlagLoaderConfig{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, &defaultsConfig{Host: "localhost", Port: 80}, cfg)
+}
+
+func TestLoad_defaultsZeroValues(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(filename, []byte("enabled: false\nport: 0\nname: \"\"\n"), 0o644))
+
+	cfg := &struct {
+		Enabled bool   `default:"true"`
+		Port    int    `default:"80"`
+		Name    string `default:"foo"`
+	}{}
+
+	_, err := Load(cfg,
+		NewFileLoader(FileLoaderConfig{Filename: filename}),
+		NewEnvLoader(EnvLoaderConfig{}),
+		NewFlagLoader(FlagLoaderConfig{}),
+	)
+	require.NoError(t, err)
+
+	assert.False(t, cfg.Enabled)
+	assert.Equal(t, 0, cfg.Port)
+	assert.Equal(t, "", cfg.Name)
+}
+
+func TestLoader_defaults(t *testing.T) {
+	t.Setenv("GONFIG_HOST", "example.com")
+
+	loaders := []Loader{
+		NewFileLoader(FileLoaderConfig{Filename: "./fixtures/notfound.yml"}),
+		NewDotEnvLoader(DotEnvLoaderConfig{Filename: "./fixtures/notfound.env"}),
+		NewEnvLoader(EnvLoaderConfig{}),
+		NewFlagLoader(FlagLoaderConfig{}),
+	}
+
+	for _, loader := range loaders {
+		cfg := &defaultsConfig{}
+
+		_, err := loader.Load(cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, 80, cfg.Port, "%T", loader)
+	}
+}
+
+func TestLoad_error(t *testing.T) {
+	cfg := &example.Config{}
+
+	res, err := Load(cfg,
+		NewFlagLoader(FlagLoaderConfig{Args: []string{"--logLevel=debug"}}),
+		errLoader{},
+	)
+	require.Error(t, err)
+
+	// loaders without a source are applied first
+	assert.Empty(t, res.Loaders)
+	assert.Empty(t, cfg.LogLevel)
+}
+
+func TestChain_validate(t *testing.T) {
+	chain := NewChain(ChainConfig{
+		Loaders: []Loader{
+			NewEnvLoader(EnvLoaderConfig{Prefix: "MYAPP_"}),
+			NewFlagLoader(FlagLoaderConfig{Args: []string{"--server.ftp.port=0"}}),
+		},
+		Validate: true,
+	})
+
+	_, err := chain.Load(&example.Config{})
+	require.EqualError(t, err, "invalid configuration: "+
+		"server.ftp.host: is required (env: MYAPP_SERVER_FTP_HOST, flag: --server.ftp.host); "+
+		"server.ftp.port: is required (env: MYAPP_SERVER_FTP_PORT, flag: --server.ftp.port); "+
+		"download: is required (env: MYAPP_DOWNLOAD, flag: --download)")
+}
+
+type remoteLoader struct{}
+
+func (remoteLoader) Load(cfg interface{}) (bool, error) {
+	return true, nil
+}
+
+func (remoteLoader) GetFilenames() []string {
+	return []string{"https://example.com/myapp.yml"}
+}
+
+func (remoteLoader) EnvPrefix() string {
+	return "REMOTE_"
+}
+
+func TestChain_customLoader(t *testing.T) {
+	chain := NewChain(ChainConfig{
+		Loaders:  []Loader{remoteLoader{}},
+		Validate: true,
+	})
+
+	_, err := chain.Load(&struct {
+		Host string `validate:"required"`
+	}{})
+	require.EqualError(t, err, "invalid configuration: host: is required (env: REMOTE_HOST, flag: --host)")
+
+	res := chain.GetResult()
+	require.Len(t, res.Loaders, 1)
+	assert.Equal(t, []string{"https://example.com/myapp.yml"}, res.Loaders[0].Files)
+}
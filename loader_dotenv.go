@@ -0,0 +1,120 @@
+package gonfig
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/crazy-max/gonfig/env"
+	"github.com/pkg/errors"
+)
+
+// DefaultDotEnvFilename is the .env file read by a DotEnvLoader when none is set.
+const DefaultDotEnvFilename = ".env"
+
+// DotEnvLoader is the structure representring a .env file loader.
+type DotEnvLoader struct {
+	filename   string
+	vars       []string
+	provenance Provenance
+	cfg        DotEnvLoaderConfig
+}
+
+// DotEnvLoaderConfig loads a configuration from the variables defined in a .env file,
+// following the same prefix rules as EnvLoaderConfig, without setting them in the environment.
+type DotEnvLoaderConfig struct {
+	// Filename of the .env file. Default to ".env"
+	Filename string
+	// Prefix to use. Default to "GONFIG_"
+	Prefix string
+}
+
+// NewDotEnvLoader creates a new Loader from the DotEnvLoaderConfig cfg.
+func NewDotEnvLoader(cfg DotEnvLoaderConfig) *DotEnvLoader {
+	return &DotEnvLoader{
+		cfg: cfg,
+	}
+}
+
+// GetFilename returns the .env file if found.
+func (l *DotEnvLoader) GetFilename() string {
+	return l.filename
+}
+
+// GetFilenames returns the .env file in a slice if found.
+func (l *DotEnvLoader) GetFilenames() []string {
+	if l.filename == "" {
+		return nil
+	}
+	return []string{l.filename}
+}
+
+// GetVars returns the variables found in the .env file.
+func (l *DotEnvLoader) GetVars() []string {
+	return l.vars
+}
+
+// GetProvenance returns the variable each value comes from.
+func (l *DotEnvLoader) GetProvenance() Provenance {
+	return l.provenance
+}
+
+// Source returns the kind of resource read by the loader.
+func (l *DotEnvLoader) Source() Source {
+	return SourceDotEnv
+}
+
+// Load loads the configuration from the .env file.
+// The zero fields of cfg are first set to the value of their default tag.
+func (l *DotEnvLoader) Load(cfg interface{}) (bool, error) {
+	if err := fillDefaults(cfg); err != nil {
+		return false, err
+	}
+	return l.load(cfg)
+}
+
+func (l *DotEnvLoader) load(cfg interface{}) (bool, error) {
+	prefix := l.cfg.Prefix
+	if prefix == "" {
+		prefix = env.DefaultNamePrefix
+	}
+
+	filename := l.cfg.Filename
+	if filename == "" {
+		filename = DefaultDotEnvFilename
+	}
+
+	l.filename = ""
+	l.vars = nil
+	l.provenance = Provenance{}
+
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return false, err
+	}
+
+	environ, err := env.ReadDotEnv(filename)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to read .env file")
+	}
+
+	l.filename = filename
+	l.vars = env.FindPrefixedEnvVars(environ, prefix, cfg)
+	if len(l.vars) == 0 {
+		return false, nil
+	}
+
+	decodeOpts := env.DecodeOpts{
+		OnFill: func(path, name string) {
+			l.provenance[path] = Origin{Source: SourceDotEnv, Name: l.filename, Key: name}
+		},
+	}
+	if err := env.DecodeWithOpts(l.vars, prefix, cfg, decodeOpts); err != nil {
+		return false, errors.Wrap(err, "Failed to decode configuration from .env file")
+	}
+
+	return true, nil
+}
@@ -9,8 +9,9 @@ import (
 
 // EnvLoader is the structure representring an environment variable loader.
 type EnvLoader struct {
-	vars []string
-	cfg  EnvLoaderConfig
+	vars       []string
+	provenance Provenance
+	cfg        EnvLoaderConfig
 }
 
 // EnvLoaderConfig loads a configuration from environment variables.
@@ -31,19 +32,49 @@ func (l *EnvLoader) GetVars() []string {
 	return l.vars
 }
 
+// GetProvenance returns the environment variable each value comes from.
+func (l *EnvLoader) GetProvenance() Provenance {
+	return l.provenance
+}
+
+// EnvPrefix returns the prefix of the environment variables read by the loader.
+func (l *EnvLoader) EnvPrefix() string {
+	if l.cfg.Prefix == "" {
+		return env.DefaultNamePrefix
+	}
+	return l.cfg.Prefix
+}
+
+// Source returns the kind of resource read by the loader.
+func (l *EnvLoader) Source() Source {
+	return SourceEnv
+}
+
 // Load loads the configuration from the environment variables.
+// The zero fields of cfg are first set to the value of their default tag.
 func (l *EnvLoader) Load(cfg interface{}) (bool, error) {
-	prefix := l.cfg.Prefix
-	if prefix == "" {
-		prefix = env.DefaultNamePrefix
+	if err := fillDefaults(cfg); err != nil {
+		return false, err
 	}
+	return l.load(cfg)
+}
+
+func (l *EnvLoader) load(cfg interface{}) (bool, error) {
+	prefix := l.EnvPrefix()
+
+	l.provenance = Provenance{}
 
 	l.vars = env.FindPrefixedEnvVars(os.Environ(), prefix, cfg)
 	if len(l.vars) == 0 {
 		return false, nil
 	}
 
-	if err := env.Decode(l.vars, prefix, cfg); err != nil {
+	decodeOpts := env.DecodeOpts{
+		OnFill: func(path, name string) {
+			l.provenance[path] = Origin{Source: SourceEnv, Name: name}
+		},
+	}
+	if err := env.DecodeWithOpts(l.vars, prefix, cfg, decodeOpts); err != nil {
 		return false, errors.Wrap(err, "Failed to decode configuration from environment variables")
 	}
 
@@ -1,19 +1,48 @@
 package gonfig
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/crazy-max/gonfig/file"
+	"github.com/pkg/errors"
 )
 
 // FileLoader is the structure representring a file loader.
 type FileLoader struct {
-	filename string
-	cfg      FileLoaderConfig
+	filename   string
+	filenames  []string
+	provenance Provenance
+	cfg        FileLoaderConfig
 }
 
 // FileLoaderConfig loads a configuration from a file.
 type FileLoaderConfig struct {
 	Filename string
 	Finder   Finder
+	// Interpolate expands the environment variable references in the values of the file
+	// such as ${VAR}, ${VAR:-default} or ${VAR:?error}.
+	Interpolate bool
+	// Merge loads all the files found instead of the first one.
+	// The files are merged so that the first found (Filename, then the Finder paths in order)
+	// overrides the next ones, e.g. a project file overrides a user file that overrides a system one.
+	Merge bool
+	// DropInDirs are directories whose files with one of the DropInExtensions
+	// are merged on top of the configuration file, in lexical order, e.g. /etc/myapp/conf.d.
+	// Missing directories and files without any configuration, e.g. with only comments, are ignored.
+	DropInDirs []string
+	// DropInExtensions are the extensions of the files read from the DropInDirs, whatever the Finder extensions.
+	// Default to file.SupportedExtensions.
+	DropInExtensions []string
+	// Profiles are merged on top of each configuration file found, in order,
+	// from the files named <basename>.<profile>.<ext> next to it, e.g. myapp.production.yml.
+	Profiles []string
+	// ProfilesEnv is the name of an environment variable holding comma-separated profiles
+	// that replace Profiles when set, e.g. MYAPP_PROFILE.
+	ProfilesEnv string
+	// StrictProfiles fails the loading if no file is found for a profile.
+	StrictProfiles bool
 }
 
 // NewFileLoader creates a new Loader fromt the FileLoaderConfig cfg.
@@ -24,26 +53,220 @@ func NewFileLoader(cfg FileLoaderConfig) *FileLoader {
 }
 
 // GetFilename returns the configuration file if any.
+// In merge mode, it returns the file with the highest precedence.
 func (l *FileLoader) GetFilename() string {
 	return l.filename
 }
 
+// GetFilenames returns all the files that have been loaded, including the drop-in files,
+// in the order they have been merged: each file overrides the previous ones.
+func (l *FileLoader) GetFilenames() []string {
+	return l.filenames
+}
+
+// GetProvenance returns the file and key each value comes from.
+func (l *FileLoader) GetProvenance() Provenance {
+	return l.provenance
+}
+
+// Source returns the kind of resource read by the loader.
+func (l *FileLoader) Source() Source {
+	return SourceFile
+}
+
 // Load loads the configuration from a file and/or finders.
+// The zero fields of cfg are first set to the value of their default tag.
 func (l *FileLoader) Load(cfg interface{}) (bool, error) {
+	if err := fillDefaults(cfg); err != nil {
+		return false, err
+	}
+	return l.load(cfg)
+}
+
+func (l *FileLoader) load(cfg interface{}) (bool, error) {
 	var err error
 
-	l.filename, err = l.cfg.Finder.Find(l.cfg.Filename)
+	l.provenance = Provenance{}
+
+	l.filenames, err = l.find()
 	if err != nil {
 		return false, err
 	}
 
-	if len(l.filename) == 0 {
+	if len(l.filenames) == 0 {
 		return false, nil
 	}
 
-	if err = file.Decode(l.filename, cfg); err != nil {
+	decodeOpts := l.decodeOpts()
+	decodeOpts.OnFill = func(path, key, filename string) {
+		l.provenance[path] = Origin{Source: SourceFile, Name: filename, Key: key}
+	}
+	if err = file.DecodeFilesWithOpts(l.filenames, cfg, decodeOpts); err != nil {
 		return false, err
 	}
 
 	return true, nil
 }
+
+// find returns the files to load in the order they have to be merged.
+func (l *FileLoader) find() (filenames []string, err error) {
+	if l.cfg.Merge {
+		found, err := l.cfg.Finder.FindAll(l.cfg.Filename)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(found) - 1; i >= 0; i-- {
+			filenames = append(filenames, found[i])
+		}
+	} else {
+		filename, err := l.cfg.Finder.Find(l.cfg.Filename)
+		if err != nil {
+			return nil, err
+		}
+		if len(filename) > 0 {
+			filenames = append(filenames, filename)
+		}
+	}
+
+	l.filename = ""
+	if len(filenames) > 0 {
+		l.filename = filenames[len(filenames)-1]
+	}
+
+	filenames, err = l.withProfiles(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	dropIns, err := l.findDropIns()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(filenames, dropIns...), nil
+}
+
+// withProfiles adds the profile files found after each file of filenames.
+func (l *FileLoader) withProfiles(filenames []string) ([]string, error) {
+	profiles := l.getProfiles()
+	if len(profiles) == 0 {
+		return filenames, nil
+	}
+
+	var result []string
+	found := make(map[string]bool, len(profiles))
+
+	for _, filename := range filenames {
+		result = append(result, filename)
+
+		ext := filepath.Ext(filename)
+		for _, profile := range profiles {
+			profileFile := strings.TrimSuffix(filename, ext) + "." + profile + ext
+
+			_, err := os.Stat(profileFile)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, profileFile)
+			found[profile] = true
+		}
+	}
+
+	if l.cfg.StrictProfiles {
+		for _, profile := range profiles {
+			if !found[profile] {
+				return nil, errors.Errorf("no configuration file found for profile %q", profile)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (l *FileLoader) getProfiles() []string {
+	profiles := l.cfg.Profiles
+
+	if l.cfg.ProfilesEnv != "" {
+		if value, ok := os.LookupEnv(l.cfg.ProfilesEnv); ok {
+			profiles = strings.Split(value, ",")
+		}
+	}
+
+	var result []string
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			result = append(result, profile)
+		}
+	}
+
+	return result
+}
+
+func (l *FileLoader) findDropIns() ([]string, error) {
+	var filenames []string
+
+	for _, dir := range l.cfg.DropInDirs {
+		dir = os.ExpandEnv(dir)
+
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			if !l.isDropInFile(entry.Name()) {
+				continue
+			}
+
+			filename, err := filepath.Abs(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			// placeholder fragments, e.g. with only comments, are skipped
+			empty, err := file.IsEmpty(filename)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to read drop-in file %s", filename)
+			}
+			if empty {
+				continue
+			}
+
+			filenames = append(filenames, filename)
+		}
+	}
+
+	return filenames, nil
+}
+
+func (l *FileLoader) decodeOpts() file.DecodeOpts {
+	return file.DecodeOpts{
+		Interpolate: l.cfg.Interpolate,
+	}
+}
+
+// isDropInFile reports whether filename has one of the extensions of the drop-in files.
+func (l *FileLoader) isDropInFile(filename string) bool {
+	extensions := l.cfg.DropInExtensions
+	if len(extensions) == 0 {
+		extensions = file.SupportedExtensions()
+	}
+
+	for _, ext := range extensions {
+		if strings.EqualFold(filepath.Ext(filename), "."+strings.TrimPrefix(ext, ".")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package gonfig
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/crazy-max/gonfig/file"
+	"github.com/crazy-max/gonfig/parser"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	defaultWatchDebounce = 100 * time.Millisecond
+)
+
+type initializer interface {
+	SetDefaults()
+}
+
+// FileWatcher is the structure representing a watcher that reloads
+// the configuration files of a FileLoader when one of them changes.
+type FileWatcher struct {
+	loader *FileLoader
+	cfg    FileWatcherConfig
+
+	mu       sync.RWMutex
+	current  interface{}
+	onChange []func(oldCfg, newCfg interface{})
+	onError  []func(err error)
+}
+
+// FileWatcherConfig watches the configuration file of a FileLoader.
+type FileWatcherConfig struct {
+	// Interval between two checks of the file. Default to 1s.
+	Interval time.Duration
+	// Debounce is the delay without any further change before the file is reloaded. Default to 100ms.
+	Debounce time.Duration
+	// New returns the fresh configuration the file is decoded into on change.
+	// Default to a new value of the watched configuration type,
+	// initialized with its default tags and SetDefaults if any.
+	New func() interface{}
+}
+
+// NewFileWatcher creates a new watcher of the file resolved by loader.
+func NewFileWatcher(loader *FileLoader, cfg FileWatcherConfig) *FileWatcher {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultWatchInterval
+	}
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = defaultWatchDebounce
+	}
+
+	return &FileWatcher{
+		loader: loader,
+		cfg:    cfg,
+	}
+}
+
+// OnChange registers a callback called with the previous and the new configuration
+// each time the file has been reloaded successfully.
+func (w *FileWatcher) OnChange(fn func(oldCfg, newCfg interface{})) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// OnError registers a callback called when the file cannot be checked or decoded.
+// The last good configuration is kept in that case.
+func (w *FileWatcher) OnError(fn func(err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, fn)
+}
+
+// Current returns the last good configuration.
+func (w *FileWatcher) Current() interface{} {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Watch watches the configuration files until ctx is done.
+// cfg is the configuration currently loaded and must be a pointer.
+func (w *FileWatcher) Watch(ctx context.Context, cfg interface{}) error {
+	if reflect.TypeOf(cfg) == nil || reflect.TypeOf(cfg).Kind() != reflect.Pointer {
+		return errors.New("configuration must be a pointer")
+	}
+
+	filenames := w.loader.GetFilenames()
+	if len(filenames) == 0 {
+		var err error
+		filenames, err = w.loader.find()
+		if err != nil {
+			return err
+		}
+		if len(filenames) == 0 {
+			return errors.New("no configuration file to watch")
+		}
+	}
+
+	w.mu.Lock()
+	w.current = cfg
+	w.mu.Unlock()
+
+	last := make(map[string]os.FileInfo, len(filenames))
+	for _, filename := range filenames {
+		fi, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		last[filename] = fi
+	}
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	debounce := time.NewTimer(w.cfg.Debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, filename := range filenames {
+				fi, err := os.Stat(filename)
+				if os.IsNotExist(err) {
+					// the file can be missing while being replaced
+					continue
+				}
+				if err != nil {
+					w.notifyError(err)
+					continue
+				}
+				if fi.ModTime().Equal(last[filename].ModTime()) && fi.Size() == last[filename].Size() {
+					continue
+				}
+				last[filename] = fi
+				debounce.Reset(w.cfg.Debounce)
+			}
+		case <-debounce.C:
+			w.reload(filenames)
+		}
+	}
+}
+
+func (w *FileWatcher) reload(filenames []string) {
+	newCfg, err := w.newConfig()
+	if err != nil {
+		w.notifyError(err)
+		return
+	}
+
+	if err := file.DecodeFilesWithOpts(filenames, newCfg, w.loader.decodeOpts()); err != nil {
+		w.notifyError(err)
+		return
+	}
+
+	w.mu.Lock()
+	oldCfg := w.current
+	w.current = newCfg
+	callbacks := w.onChange
+	w.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(oldCfg, newCfg)
+	}
+}
+
+func (w *FileWatcher) newConfig() (interface{}, error) {
+	if w.cfg.New != nil {
+		return w.cfg.New(), nil
+	}
+
+	w.mu.RLock()
+	rType := reflect.TypeOf(w.current).Elem()
+	w.mu.RUnlock()
+
+	cfg := reflect.New(rType).Interface()
+	if err := parser.FillDefaults(cfg); err != nil {
+		return nil, err
+	}
+	if i, ok := cfg.(initializer); ok {
+		i.SetDefaults()
+	}
+
+	return cfg, nil
+}
+
+func (w *FileWatcher) notifyError(err error) {
+	w.mu.RLock()
+	callbacks := w.onError
+	w.mu.RUnlock()
+
+	for _, fn := range callbacks {
+		fn(err)
+	}
+}
@@ -0,0 +1,93 @@
+package gonfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchedConfig struct {
+	Foo string
+	Bar int
+}
+
+func (c *watchedConfig) SetDefaults() {
+	c.Bar = 42
+}
+
+func TestFileWatcher(t *testing.T) {
+	cfgfile := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: first\n"), 0o644))
+
+	fileLoader := NewFileLoader(FileLoaderConfig{Filename: cfgfile})
+
+	cfg := &watchedConfig{}
+	found, err := fileLoader.Load(cfg)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	watcher := NewFileWatcher(fileLoader, FileWatcherConfig{
+		Interval: 10 * time.Millisecond,
+		Debounce: 30 * time.Millisecond,
+	})
+
+	changes := make(chan [2]interface{}, 1)
+	watcher.OnChange(func(oldCfg, newCfg interface{}) {
+		changes <- [2]interface{}{oldCfg, newCfg}
+	})
+	errs := make(chan error, 1)
+	watcher.OnError(func(err error) {
+		errs <- err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Watch(ctx, cfg)
+	}()
+
+	// wait for the watcher to take the initial state of the file
+	require.Eventually(t, func() bool { return watcher.Current() != nil }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: second value\n"), 0o644))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, &watchedConfig{Foo: "first"}, change[0])
+		assert.Equal(t, &watchedConfig{Foo: "second value", Bar: 42}, change[1])
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for change")
+	}
+
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: [invalid\n"), 0o644))
+
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+	case <-changes:
+		t.Fatal("unexpected change")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for error")
+	}
+
+	assert.Equal(t, &watchedConfig{Foo: "second value", Bar: 42}, watcher.Current())
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestFileWatcher_noFile(t *testing.T) {
+	watcher := NewFileWatcher(NewFileLoader(FileLoaderConfig{}), FileWatcherConfig{})
+
+	err := watcher.Watch(context.Background(), &watchedConfig{})
+	require.Error(t, err)
+}
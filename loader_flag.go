@@ -1,19 +1,45 @@
 package gonfig
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/crazy-max/gonfig/flag"
 	"github.com/pkg/errors"
 )
 
+// ErrHelp is returned by the FlagLoader when the -h or --help flag is set, once the usage has been written.
+var ErrHelp = flag.ErrHelp
+
 // FlagLoader is the structure representring a flag loader.
 type FlagLoader struct {
-	cfg FlagLoaderConfig
+	provenance Provenance
+	cfg        FlagLoaderConfig
+	command    string
+	args       []string
 }
 
 // FlagLoaderConfig loads a configuration from flags.
 type FlagLoaderConfig struct {
 	// Args are command line arguments.
 	Args []string
+	// Usage holds the options used to write the usage when the -h or --help flag is set.
+	Usage flag.UsageOpts
+	// Commands are the subcommands selected by the first non-flag argument.
+	// If empty, all the non-flag arguments are positional arguments.
+	Commands []Command
+}
+
+// Command is a subcommand of the FlagLoader, e.g. serve in "myapp --debug serve --port 80".
+type Command struct {
+	// Name of the command.
+	Name string
+	// Description of the command, shown in the usage.
+	Description string
+	// Config is a pointer to the struct into which the flags following the command are decoded,
+	// e.g. a field of the configuration or a per-command configuration, whose default tags are applied first.
+	// If nil, they are decoded into the configuration.
+	Config interface{}
 }
 
 // NewFlagLoader creates a new Loader from the FlagLoaderConfig cfg.
@@ -23,15 +49,120 @@ func NewFlagLoader(cfg FlagLoaderConfig) *FlagLoader {
 	}
 }
 
+// GetProvenance returns the flag each value comes from.
+// The paths of the values set by a command are prefixed by the name of the command.
+func (l *FlagLoader) GetProvenance() Provenance {
+	return l.provenance
+}
+
+// GetCommand returns the name of the command selected by the last load, if any.
+func (l *FlagLoader) GetCommand() string {
+	return l.command
+}
+
+// GetArgs returns the positional arguments of the last load, following the flags and the command if any.
+func (l *FlagLoader) GetArgs() []string {
+	return l.args
+}
+
+// Source returns the kind of resource read by the loader.
+func (l *FlagLoader) Source() Source {
+	return SourceFlag
+}
+
 // Load loads the configuration from flags.
+// The zero fields of cfg are first set to the value of their default tag.
 func (l *FlagLoader) Load(cfg interface{}) (bool, error) {
+	if err := fillDefaults(cfg); err != nil {
+		return false, err
+	}
+	return l.load(cfg)
+}
+
+func (l *FlagLoader) load(cfg interface{}) (bool, error) {
+	l.provenance = Provenance{}
+	l.command = ""
+	l.args = nil
+
 	if len(l.cfg.Args) == 0 {
 		return false, nil
 	}
 
-	if err := flag.Decode(l.cfg.Args, cfg); err != nil {
+	if err := l.decode(l.cfg.Args, cfg, "", &l.args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			usage := l.cfg.Usage
+			for _, command := range l.cfg.Commands {
+				usage.Commands = append(usage.Commands, flag.Command{Name: command.Name, Description: command.Description})
+			}
+			return false, writeUsage(cfg, usage)
+		}
 		return false, errors.Wrap(err, "Failed to decode configuration from flags")
 	}
 
+	if len(l.cfg.Commands) == 0 || len(l.args) == 0 {
+		return len(l.provenance) > 0 || len(l.args) > 0, nil
+	}
+
+	command, ok := l.getCommand(l.args[0])
+	if !ok {
+		return false, errors.Errorf("Unknown command %q", l.args[0])
+	}
+	l.command = command.Name
+
+	// the flags are recorded under the command name only if they are decoded into its own config
+	commandCfg, prefix := command.Config, command.Name
+	if commandCfg == nil {
+		commandCfg, prefix = cfg, ""
+	} else if err := fillDefaults(commandCfg); err != nil {
+		return false, err
+	}
+
+	if err := l.decode(l.args[1:], commandCfg, prefix, &l.args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			usage := l.cfg.Usage
+			if usage.Name == "" {
+				usage.Name = filepath.Base(os.Args[0])
+			}
+			usage.Name += " " + command.Name
+			return false, writeUsage(commandCfg, usage)
+		}
+		return false, errors.Wrapf(err, "Failed to decode configuration of command %s from flags", command.Name)
+	}
+
 	return true, nil
 }
+
+// decode decodes the flags of args into element, records their origin under prefix
+// and sets remaining to the arguments following the flags.
+func (l *FlagLoader) decode(args []string, element interface{}, prefix string, remaining *[]string) error {
+	decodeOpts := flag.DecodeOpts{
+		OnFill: func(path, name string) {
+			if prefix != "" {
+				path = prefix + "." + path
+			}
+			l.provenance[path] = Origin{Source: SourceFlag, Name: name}
+		},
+		OnArgs: func(args []string) {
+			*remaining = args
+		},
+	}
+
+	return flag.DecodeWithOpts(args, element, decodeOpts)
+}
+
+func (l *FlagLoader) getCommand(name string) (Command, bool) {
+	for _, command := range l.cfg.Commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+// writeUsage writes the usage of element and returns ErrHelp.
+func writeUsage(element interface{}, opts flag.UsageOpts) error {
+	if err := flag.Usage(element, opts); err != nil {
+		return errors.Wrap(err, "Failed to write usage")
+	}
+	return ErrHelp
+}
@@ -1,12 +1,16 @@
 package gonfig
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	example "github.com/crazy-max/gonfig/contrib/example/config"
 	"github.com/crazy-max/gonfig/env"
+	"github.com/crazy-max/gonfig/flag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,6 +92,93 @@ func TestEnvLoader(t *testing.T) {
 	}
 }
 
+func TestDotEnvLoader(t *testing.T) {
+	dir := t.TempDir()
+
+	content := `# local settings
+export MYAPP_SERVER_FTP_HOST=test.rebex.net
+MYAPP_SERVER_FTP_USERNAME='demo'
+MYAPP_SERVER_FTP_PASSWORD="pass\"word"
+MYAPP_SERVER_FTP_SOURCES=/
+OTHER_VAR=foo
+`
+	filename := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+
+	testCases := []struct {
+		desc     string
+		filename string
+		found    bool
+		expected *example.ServerFTP
+	}{
+		{
+			desc:     "missing file",
+			filename: filepath.Join(dir, ".env.missing"),
+			found:    false,
+		},
+		{
+			desc:     "found",
+			filename: filename,
+			found:    true,
+			expected: &example.ServerFTP{
+				Host:     "test.rebex.net",
+				Username: "demo",
+				Password: `pass"word`,
+				Sources:  []string{"/"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			dotEnvLoader := NewDotEnvLoader(DotEnvLoaderConfig{
+				Filename: test.filename,
+				Prefix:   "MYAPP_",
+			})
+
+			var cfg example.Config
+			found, err := dotEnvLoader.Load(&cfg)
+			require.NoError(t, err)
+			assert.Equal(t, test.found, found)
+
+			if test.expected == nil {
+				assert.Nil(t, cfg.Server)
+				return
+			}
+
+			assert.Equal(t, test.expected.Host, cfg.Server.FTP.Host)
+			assert.Equal(t, test.expected.Username, cfg.Server.FTP.Username)
+			assert.Equal(t, test.expected.Password, cfg.Server.FTP.Password)
+			assert.Equal(t, test.expected.Sources, cfg.Server.FTP.Sources)
+
+			assert.Len(t, dotEnvLoader.GetVars(), 4)
+			assert.Equal(t, Origin{Source: SourceDotEnv, Name: filename, Key: "MYAPP_SERVER_FTP_HOST"}, dotEnvLoader.GetProvenance()["server.ftp.host"])
+
+			_, ok := os.LookupEnv("MYAPP_SERVER_FTP_HOST")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestDotEnvLoader_precedence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(filename, []byte("MYAPP_SERVER_FTP_HOST=dotenv.local\nMYAPP_SERVER_FTP_USERNAME=demo\n"), 0o644))
+
+	t.Setenv("MYAPP_SERVER_FTP_HOST", "env.local")
+
+	var cfg example.Config
+	res, err := Load(&cfg,
+		NewEnvLoader(EnvLoaderConfig{Prefix: "MYAPP_"}),
+		NewDotEnvLoader(DotEnvLoaderConfig{Filename: filename, Prefix: "MYAPP_"}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Source{SourceDotEnv, SourceEnv}, res.Sources())
+	assert.Equal(t, []string{filename}, res.Loaders[0].Files)
+	assert.Equal(t, "env.local", cfg.Server.FTP.Host)
+	assert.Equal(t, "demo", cfg.Server.FTP.Username)
+}
+
 func TestFileLoader(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -188,6 +279,269 @@ func TestFileLoader(t *testing.T) {
 	}
 }
 
+func TestFileLoader_merge(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"system.yml":  "server:\n  ftp:\n    host: system.local\n    port: 21\n    username: system\n",
+		"user.toml":   "[server.ftp]\n  host = \"user.local\"\n  port = 2121\n",
+		"project.yml": "server:\n  ftp:\n    host: project.local\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	fileLoader := NewFileLoader(FileLoaderConfig{
+		Finder: Finder{
+			BasePaths:  []string{filepath.Join(dir, "project"), filepath.Join(dir, "user"), filepath.Join(dir, "system")},
+			Extensions: []string{"toml", "yml"},
+		},
+		Merge: true,
+	})
+
+	cfg := &example.Config{}
+	found, err := fileLoader.Load(cfg)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	assert.Equal(t, "project.local", cfg.Server.FTP.Host)
+	assert.Equal(t, 2121, cfg.Server.FTP.Port)
+	assert.Equal(t, "system", cfg.Server.FTP.Username)
+
+	expectedFiles := []string{
+		filepath.Join(dir, "system.yml"),
+		filepath.Join(dir, "user.toml"),
+		filepath.Join(dir, "project.yml"),
+	}
+	assert.Equal(t, expectedFiles, fileLoader.GetFilenames())
+	assert.Equal(t, filepath.Join(dir, "project.yml"), fileLoader.GetFilename())
+
+	expectedProvenance := Provenance{
+		"server.ftp.host":     {Source: SourceFile, Name: filepath.Join(dir, "project.yml"), Key: "server.ftp.host"},
+		"server.ftp.port":     {Source: SourceFile, Name: filepath.Join(dir, "user.toml"), Key: "server.ftp.port"},
+		"server.ftp.username": {Source: SourceFile, Name: filepath.Join(dir, "system.yml"), Key: "server.ftp.username"},
+	}
+	assert.Equal(t, expectedProvenance, fileLoader.GetProvenance())
+
+	// without merge, only the first file found is loaded
+	fileLoader = NewFileLoader(FileLoaderConfig{
+		Finder: Finder{
+			BasePaths:  []string{filepath.Join(dir, "project"), filepath.Join(dir, "user"), filepath.Join(dir, "system")},
+			Extensions: []string{"toml", "yml"},
+		},
+	})
+
+	cfg = &example.Config{}
+	_, err = fileLoader.Load(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "project.local", cfg.Server.FTP.Host)
+	assert.Equal(t, 21, cfg.Server.FTP.Port)
+	assert.Empty(t, cfg.Server.FTP.Username)
+	assert.Equal(t, expectedFiles[2:], fileLoader.GetFilenames())
+}
+
+func TestFileLoader_dropInDirs(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"myapp.yml":              "server:\n  ftp:\n    host: main.local\n    port: 21\n    username: main\n",
+		"conf.d/10-host.toml":    "[server.ftp]\n  host = \"dropin.local\"\n  port = 2121\n",
+		"conf.d/20-port.json":    `{"server": {"ftp": {"port": 2222}}}`,
+		"conf.d/00-defaults.yml": "# placeholder installed by the package\n",
+		"conf.d/30-empty.toml":   "",
+		"conf.d/README.md":       "ignored",
+		"conf.d/40-user.hcl":     "server {\n  ftp {\n    username = \"dropin\"\n  }\n}\n",
+		"conf.d/sub/30-user.yml": "server:\n  ftp:\n    username: ignored\n",
+		"local.d/10-user.yaml":   "server:\n  ftp:\n    username: local\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	fileLoader := NewFileLoader(FileLoaderConfig{
+		Filename:   filepath.Join(dir, "myapp.yml"),
+		DropInDirs: []string{filepath.Join(dir, "conf.d"), filepath.Join(dir, "missing.d"), filepath.Join(dir, "local.d")},
+	})
+
+	cfg := &example.Config{}
+	result, err := Load(cfg, fileLoader)
+	require.NoError(t, err)
+	assert.True(t, result.Found())
+
+	assert.Equal(t, "dropin.local", cfg.Server.FTP.Host)
+	assert.Equal(t, 2222, cfg.Server.FTP.Port)
+	assert.Equal(t, "local", cfg.Server.FTP.Username)
+
+	expectedFiles := []string{
+		filepath.Join(dir, "myapp.yml"),
+		filepath.Join(dir, "conf.d", "10-host.toml"),
+		filepath.Join(dir, "conf.d", "20-port.json"),
+		filepath.Join(dir, "conf.d", "40-user.hcl"),
+		filepath.Join(dir, "local.d", "10-user.yaml"),
+	}
+	assert.Equal(t, expectedFiles, fileLoader.GetFilenames())
+	assert.Equal(t, filepath.Join(dir, "myapp.yml"), fileLoader.GetFilename())
+
+	require.Len(t, result.Loaders, 1)
+	assert.Equal(t, expectedFiles, result.Loaders[0].Files)
+	assert.Equal(t, filepath.Join(dir, "local.d", "10-user.yaml"), result.Provenance["server.ftp.username"].Name)
+}
+
+func TestFileLoader_dropInDirsExtensions(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "conf.d"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "10-host.hcl"), []byte("server {\n  ftp {\n    host = \"dropin.local\"\n  }\n}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "20-port.toml"), []byte("[server.ftp]\nport = 2222\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "README.md"), []byte("# Drop-in files\n"), 0o644))
+
+	testCases := []struct {
+		desc     string
+		cfg      FileLoaderConfig
+		expected []string
+	}{
+		{
+			desc: "supported extensions whatever the finder ones",
+			cfg: FileLoaderConfig{
+				Finder: Finder{Extensions: []string{"yaml", "yml"}},
+			},
+			expected: []string{"10-host.hcl", "20-port.toml"},
+		},
+		{
+			desc: "drop-in extensions",
+			cfg: FileLoaderConfig{
+				DropInExtensions: []string{"toml"},
+			},
+			expected: []string{"20-port.toml"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			test.cfg.DropInDirs = []string{filepath.Join(dir, "conf.d")}
+			fileLoader := NewFileLoader(test.cfg)
+
+			found, err := fileLoader.Load(&example.Config{})
+			require.NoError(t, err)
+			assert.True(t, found)
+
+			var expected []string
+			for _, name := range test.expected {
+				expected = append(expected, filepath.Join(dir, "conf.d", name))
+			}
+			assert.Equal(t, expected, fileLoader.GetFilenames())
+		})
+	}
+}
+
+func TestFileLoader_dropInDirsEmpty(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "conf.d"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "00-defaults.yml"), []byte("# server:\n#   ftp:\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "00.json"), nil, 0o644))
+
+	fileLoader := NewFileLoader(FileLoaderConfig{
+		DropInDirs: []string{filepath.Join(dir, "conf.d")},
+	})
+
+	found, err := fileLoader.Load(&example.Config{})
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Empty(t, fileLoader.GetFilenames())
+}
+
+func TestFileLoader_profiles(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"myapp.yml":            "server:\n  ftp:\n    host: base.local\n    port: 21\n    username: base\n",
+		"myapp.production.yml": "server:\n  ftp:\n    host: prod.local\n    port: 2121\n",
+		"myapp.local.yml":      "server:\n  ftp:\n    port: 2222\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	testCases := []struct {
+		desc          string
+		cfg           FileLoaderConfig
+		env           string
+		expectedHost  string
+		expectedPort  int
+		expectedFiles []string
+		wantErr       string
+	}{
+		{
+			desc:          "no profile",
+			cfg:           FileLoaderConfig{},
+			expectedHost:  "base.local",
+			expectedPort:  21,
+			expectedFiles: []string{"myapp.yml"},
+		},
+		{
+			desc:          "profiles",
+			cfg:           FileLoaderConfig{Profiles: []string{"production", "local"}},
+			expectedHost:  "prod.local",
+			expectedPort:  2222,
+			expectedFiles: []string{"myapp.yml", "myapp.production.yml", "myapp.local.yml"},
+		},
+		{
+			desc:          "missing profile",
+			cfg:           FileLoaderConfig{Profiles: []string{"staging", "local"}},
+			expectedHost:  "base.local",
+			expectedPort:  2222,
+			expectedFiles: []string{"myapp.yml", "myapp.local.yml"},
+		},
+		{
+			desc:    "missing profile in strict mode",
+			cfg:     FileLoaderConfig{Profiles: []string{"staging", "local"}, StrictProfiles: true},
+			wantErr: `no configuration file found for profile "staging"`,
+		},
+		{
+			desc:          "profiles from environment variable",
+			cfg:           FileLoaderConfig{Profiles: []string{"local"}, ProfilesEnv: "GONFIG_TEST_PROFILE"},
+			env:           "production",
+			expectedHost:  "prod.local",
+			expectedPort:  2121,
+			expectedFiles: []string{"myapp.yml", "myapp.production.yml"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.env != "" {
+				t.Setenv(test.cfg.ProfilesEnv, test.env)
+			}
+
+			test.cfg.Filename = filepath.Join(dir, "myapp.yml")
+			fileLoader := NewFileLoader(test.cfg)
+
+			cfg := &example.Config{}
+			_, err := fileLoader.Load(cfg)
+			if test.wantErr != "" {
+				require.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedHost, cfg.Server.FTP.Host)
+			assert.Equal(t, test.expectedPort, cfg.Server.FTP.Port)
+			assert.Equal(t, "base", cfg.Server.FTP.Username)
+
+			var expectedFiles []string
+			for _, name := range test.expectedFiles {
+				expectedFiles = append(expectedFiles, filepath.Join(dir, name))
+			}
+			assert.Equal(t, expectedFiles, fileLoader.GetFilenames())
+			assert.Equal(t, filepath.Join(dir, "myapp.yml"), fileLoader.GetFilename())
+		})
+	}
+}
+
 func TestFlagLoader(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -259,3 +613,192 @@ func TestFlagLoader(t *testing.T) {
 		})
 	}
 }
+
+func TestFlagLoader_help(t *testing.T) {
+	var buf bytes.Buffer
+
+	var cfg example.Config
+	flagLoader := NewFlagLoader(FlagLoaderConfig{
+		Args:  []string{"--help"},
+		Usage: flag.UsageOpts{Output: &buf, Name: "myapp", EnvPrefix: "MYAPP_"},
+	})
+
+	found, err := flagLoader.Load(&cfg)
+	require.ErrorIs(t, err, ErrHelp)
+	assert.False(t, found)
+
+	assert.True(t, strings.HasPrefix(buf.String(), "Usage: myapp [flags]\n"))
+	assert.Contains(t, buf.String(), "--server.ftp.host string")
+	assert.Contains(t, buf.String(), "$MYAPP_SERVER_FTP_HOST")
+}
+
+func TestFlagLoader_args(t *testing.T) {
+	var cfg example.Config
+	flagLoader := NewFlagLoader(FlagLoaderConfig{
+		Args: []string{"--server.ftp.host=test.rebex.net", "a.txt", "b.txt"},
+	})
+
+	found, err := flagLoader.Load(&cfg)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	assert.Equal(t, "test.rebex.net", cfg.Server.FTP.Host)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, flagLoader.GetArgs())
+	assert.Empty(t, flagLoader.GetCommand())
+}
+
+type commandServe struct {
+	Port int `description:"Port to listen on."`
+}
+
+type commandCopy struct {
+	Force   bool
+	Retries int `default:"3"`
+}
+
+type commandConfig struct {
+	Debug bool
+	Serve *commandServe
+}
+
+func TestFlagLoader_commands(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		args               []string
+		expected           commandConfig
+		expectedCopy       commandCopy
+		expectedCommand    string
+		expectedArgs       []string
+		expectedProvenance Provenance
+		wantErr            string
+	}{
+		{
+			desc:         "no command",
+			args:         []string{"--debug"},
+			expected:     commandConfig{Debug: true},
+			expectedArgs: []string{},
+			expectedProvenance: Provenance{
+				"debug": {Source: SourceFlag, Name: "--debug"},
+			},
+		},
+		{
+			desc:            "sub-struct",
+			args:            []string{"--debug", "serve", "--port=80", "a.txt"},
+			expected:        commandConfig{Debug: true, Serve: &commandServe{Port: 80}},
+			expectedCommand: "serve",
+			expectedArgs:    []string{"a.txt"},
+			expectedProvenance: Provenance{
+				"debug":      {Source: SourceFlag, Name: "--debug"},
+				"serve.port": {Source: SourceFlag, Name: "--port"},
+			},
+		},
+		{
+			desc:            "per-command config",
+			args:            []string{"copy", "--force", "--", "a.txt", "b.txt"},
+			expectedCopy:    commandCopy{Force: true, Retries: 3},
+			expectedCommand: "copy",
+			expectedArgs:    []string{"a.txt", "b.txt"},
+			expectedProvenance: Provenance{
+				"copy.force": {Source: SourceFlag, Name: "--force"},
+			},
+		},
+		{
+			desc:            "command without config",
+			args:            []string{"run", "--debug"},
+			expected:        commandConfig{Debug: true},
+			expectedCommand: "run",
+			expectedArgs:    []string{},
+			expectedProvenance: Provenance{
+				"debug": {Source: SourceFlag, Name: "--debug"},
+			},
+		},
+		{
+			desc:               "command without flags",
+			args:               []string{"version"},
+			expectedCommand:    "version",
+			expectedArgs:       []string{},
+			expectedProvenance: Provenance{},
+		},
+		{
+			desc:    "unknown command",
+			args:    []string{"--debug", "foo"},
+			wantErr: `Unknown command "foo"`,
+		},
+		{
+			desc:    "unknown command flag",
+			args:    []string{"serve", "--force"},
+			wantErr: "Failed to decode configuration of command serve from flags",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			var cfg commandConfig
+			var copyCfg commandCopy
+
+			cfg.Serve = &commandServe{}
+			flagLoader := NewFlagLoader(FlagLoaderConfig{
+				Args: tt.args,
+				Commands: []Command{
+					{Name: "serve", Config: cfg.Serve},
+					{Name: "copy", Config: &copyCfg},
+					{Name: "run"},
+					{Name: "version"},
+				},
+			})
+
+			found, err := flagLoader.Load(&cfg)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, found)
+
+			if tt.expected.Serve == nil {
+				tt.expected.Serve = &commandServe{}
+			}
+			assert.Equal(t, tt.expected, cfg)
+			assert.Equal(t, tt.expectedCopy, copyCfg)
+			assert.Equal(t, tt.expectedCommand, flagLoader.GetCommand())
+			assert.Equal(t, tt.expectedArgs, flagLoader.GetArgs())
+			assert.Equal(t, tt.expectedProvenance, flagLoader.GetProvenance())
+		})
+	}
+}
+
+func TestFlagLoader_commandsHelp(t *testing.T) {
+	commands := []Command{
+		{Name: "serve", Description: "Start the server.", Config: &commandServe{}},
+	}
+
+	var buf bytes.Buffer
+	flagLoader := NewFlagLoader(FlagLoaderConfig{
+		Args:     []string{"--help"},
+		Usage:    flag.UsageOpts{Output: &buf, Name: "myapp", HideEnv: true},
+		Commands: commands,
+	})
+
+	_, err := flagLoader.Load(&commandConfig{})
+	require.ErrorIs(t, err, ErrHelp)
+	assert.True(t, strings.HasPrefix(buf.String(), "Usage: myapp [flags] <command> [command flags]\n\nCommands:\n  serve  Start the server.\n"))
+
+	buf.Reset()
+	flagLoader = NewFlagLoader(FlagLoaderConfig{
+		Args:     []string{"serve", "-h"},
+		Usage:    flag.UsageOpts{Output: &buf, Name: "myapp", HideEnv: true},
+		Commands: commands,
+	})
+
+	_, err = flagLoader.Load(&commandConfig{})
+	require.ErrorIs(t, err, ErrHelp)
+
+	expected := `Usage: myapp serve [flags]
+
+Flags:
+  -h, --help  Show this help.
+  --port int  Port to listen on.
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FillDefaults sets the zero fields of element to the value of their default tag.
+// element must be a pointer to a struct.
+// The fields that are pointers to structs are left untouched:
+// their default values are set when they are allocated by Fill.
+func FillDefaults(element interface{}) error {
+	root := reflect.ValueOf(element)
+	if root.Kind() != reflect.Pointer || root.IsNil() {
+		return fmt.Errorf("a non-nil pointer is required, got %T", element)
+	}
+
+	if root.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	return newFiller(FillerOpts{}).fillDefaults(root.Elem())
+}
+
+func (f filler) fillDefaults(field reflect.Value) error {
+	for i := 0; i < field.NumField(); i++ {
+		structField := field.Type().Field(i)
+		if !IsExported(structField) {
+			continue
+		}
+
+		fd := field.Field(i)
+
+		value, ok := structField.Tag.Lookup(TagDefault)
+		if !ok {
+			if fd.Kind() == reflect.Struct && fd.Type() != reflect.TypeOf(time.Time{}) {
+				if err := f.fillDefaults(fd); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !fd.IsZero() {
+			continue
+		}
+
+		node, err := defaultNode(structField, value)
+		if err != nil {
+			return err
+		}
+
+		if err = f.fill(fd, node); err != nil {
+			return fmt.Errorf("invalid default value of field %s: %w", structField.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func defaultNode(field reflect.StructField, value string) (*Node, error) {
+	node := &Node{Name: field.Name, FieldName: field.Name, Value: value, Kind: field.Type.Kind()}
+
+	fType := field.Type
+	if fType.Kind() == reflect.Pointer {
+		fType = fType.Elem()
+	}
+
+	switch {
+	case fType.Kind() == reflect.Struct && fType != reflect.TypeOf(time.Time{}),
+		fType.Kind() == reflect.Slice && (fType.Elem().Kind() == reflect.Struct || fType.Elem().Kind() == reflect.Pointer):
+		return nil, fmt.Errorf("default value not supported on field %s (type %s)", field.Name, field.Type)
+
+	case fType.Kind() == reflect.Map:
+		node.Value = ""
+		rawValue := make(map[string]interface{})
+
+		for _, entry := range strings.Split(value, ",") {
+			k, v, ok := strings.Cut(entry, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid default map entry %q of field %s: key=value expected", entry, field.Name)
+			}
+
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			node.Children = append(node.Children, &Node{Name: k, FieldName: k, Value: v, Kind: fType.Elem().Kind()})
+			rawValue[k] = v
+		}
+
+		if fType.Elem().Kind() == reflect.Interface {
+			node.RawValue = rawValue
+			node.Children = nil
+		}
+	}
+
+	return node, nil
+}
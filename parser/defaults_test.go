@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultsNested struct {
+	Port int `default:"21"`
+}
+
+type defaults struct {
+	Host     string                 `default:"localhost"`
+	Port     int                    `default:"8080"`
+	Enabled  *bool                  `default:"true"`
+	Ratio    float64                `default:"0.5"`
+	Timeout  types.Duration         `default:"10"`
+	Delay    time.Duration          `default:"1m"`
+	Sources  []string               `default:"/src1, /src2"`
+	Ports    []int                  `default:"80,443"`
+	Headers  map[string]string      `default:"foo=bar,baz=qux"`
+	Limits   map[string]int         `default:"a=1"`
+	Raw      map[string]interface{} `default:"key=value"`
+	Nested   defaultsNested
+	NestedP  *defaultsNested
+	Explicit string `default:"default"`
+	NoTag    string
+}
+
+func TestFillDefaults(t *testing.T) {
+	element := &defaults{Explicit: "explicit"}
+
+	err := FillDefaults(element)
+	require.NoError(t, err)
+
+	enabled := true
+	expected := &defaults{
+		Host:     "localhost",
+		Port:     8080,
+		Enabled:  &enabled,
+		Ratio:    0.5,
+		Timeout:  types.Duration(10 * time.Second),
+		Delay:    time.Minute,
+		Sources:  []string{"/src1", "/src2"},
+		Ports:    []int{80, 443},
+		Headers:  map[string]string{"foo": "bar", "baz": "qux"},
+		Limits:   map[string]int{"a": 1},
+		Raw:      map[string]interface{}{"key": "value"},
+		Nested:   defaultsNested{Port: 21},
+		Explicit: "explicit",
+	}
+	assert.Equal(t, expected, element)
+}
+
+func TestFillDefaults_errors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		element interface{}
+	}{
+		{
+			desc:    "not a pointer",
+			element: defaults{},
+		},
+		{
+			desc: "invalid value",
+			element: &struct {
+				Foo int `default:"foo"`
+			}{},
+		},
+		{
+			desc: "invalid map entry",
+			element: &struct {
+				Foo map[string]string `default:"foo"`
+			}{},
+		},
+		{
+			desc: "struct",
+			element: &struct {
+				Foo *defaultsNested `default:"foo"`
+			}{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			require.Error(t, FillDefaults(test.element))
+		})
+	}
+}
+
+func TestFill_defaults(t *testing.T) {
+	element := &struct {
+		Foo *defaultsNested
+	}{}
+
+	node := &Node{
+		Name: "gonfig",
+		Kind: reflect.Pointer,
+		Children: []*Node{
+			{Name: "foo", FieldName: "Foo", Value: "true", Kind: reflect.Pointer},
+		},
+	}
+
+	err := Fill(element, node, FillerOpts{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &defaultsNested{Port: 21}, element.Foo)
+}
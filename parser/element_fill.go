@@ -21,6 +21,10 @@ type initializer interface {
 type FillerOpts struct {
 	AllowSliceAsStruct bool
 	RawSliceSeparator  string
+	// OnFill, if set, is called for each leaf node filled into the element,
+	// with its dotted path (lowercase field names, map keys kept as is)
+	// and its key (node names as read from the source), both without the root name.
+	OnFill func(path, key string)
 }
 
 // Fill populates the fields of the element using the information in node.
@@ -55,7 +59,47 @@ func (f filler) Fill(element interface{}, node *Node) error {
 		return fmt.Errorf("struct are not supported, use pointer instead")
 	}
 
-	return f.fill(root.Elem(), node)
+	if err := f.fill(root.Elem(), node); err != nil {
+		return err
+	}
+
+	if f.OnFill != nil {
+		for _, child := range node.Children {
+			f.walkFilled(child, "", "", node.Kind == reflect.Map)
+		}
+	}
+
+	return nil
+}
+
+func (f filler) walkFilled(node *Node, path, key string, mapEntry bool) {
+	if node.Disabled {
+		return
+	}
+
+	name := node.Name
+	if !mapEntry {
+		name = strings.ToLower(name)
+	}
+
+	path = joinPath(path, name)
+	key = joinPath(key, node.Name)
+
+	if len(node.Children) == 0 {
+		f.OnFill(path, key)
+		return
+	}
+
+	for _, child := range node.Children {
+		f.walkFilled(child, path, key, node.Kind == reflect.Map)
+	}
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" || name[0] == '[' {
+		return parent + name
+	}
+	return parent + "." + name
 }
 
 func (f filler) fill(field reflect.Value, node *Node) error {
@@ -112,6 +156,12 @@ func (f filler) setPtr(field reflect.Value, node *Node) error {
 	if field.IsNil() {
 		field.Set(reflect.New(field.Type().Elem()))
 
+		if field.Elem().Kind() == reflect.Struct {
+			if err := newFiller(FillerOpts{}).fillDefaults(field.Elem()); err != nil {
+				return err
+			}
+		}
+
 		if field.Type().Implements(reflect.TypeOf((*initializer)(nil)).Elem()) {
 			method := field.MethodByName("SetDefaults")
 			if method.IsValid() {
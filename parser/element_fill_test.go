@@ -1793,3 +1793,66 @@ type FiiFoo struct {
 	Fii string
 	Fuu Bouya
 }
+
+func TestFill_onFill(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+
+	element := &struct {
+		Foo struct {
+			Bar   string
+			Items []Item
+			Tags  map[string]string
+		}
+		Baz int
+	}{}
+
+	node := &Node{
+		Name: "gonfig",
+		Kind: reflect.Pointer,
+		Children: []*Node{
+			{Name: "baz", FieldName: "Baz", Value: "42", Kind: reflect.Int},
+			{
+				Name:      "FOO",
+				FieldName: "Foo",
+				Kind:      reflect.Struct,
+				Children: []*Node{
+					{Name: "Bar", FieldName: "Bar", Value: "bar", Kind: reflect.String},
+					{
+						Name:      "items",
+						FieldName: "Items",
+						Kind:      reflect.Slice,
+						Children: []*Node{
+							{Name: "[0]", Kind: reflect.Struct, Children: []*Node{
+								{Name: "name", FieldName: "Name", Value: "one", Kind: reflect.String},
+							}},
+						},
+					},
+					{
+						Name:      "tags",
+						FieldName: "Tags",
+						Kind:      reflect.Map,
+						Children: []*Node{
+							{Name: "MyTag", Value: "value", Kind: reflect.String},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	filled := map[string]string{}
+	err := Fill(element, node, FillerOpts{OnFill: func(path, key string) {
+		filled[path] = key
+	}})
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"baz":               "baz",
+		"foo.bar":           "FOO.Bar",
+		"foo.items[0].name": "FOO.items[0].name",
+		"foo.tags.MyTag":    "FOO.tags.MyTag",
+	}
+	assert.Equal(t, expected, filled)
+}
@@ -137,6 +137,10 @@ func (e encoderToFlat) getNodeValue(field reflect.Value, node *Node) string {
 		return defaultPtrValue
 	}
 
+	if value, ok := node.Tag.Lookup(TagDefault); ok && (!field.IsValid() || field.IsZero()) {
+		return value
+	}
+
 	if field.Kind() == reflect.Int64 {
 		i, _ := strconv.Atoi(node.Value)
 
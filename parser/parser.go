@@ -8,7 +8,20 @@ package parser
 // untyped nodes -> nodes augmented with metadata such as kind (inferred from element)
 // "typed" nodes -> typed element.
 func Decode(labels map[string]string, element interface{}, rootName string, filters ...string) error {
-	node, err := DecodeToNode(labels, rootName, filters...)
+	return DecodeWithOpts(labels, element, rootName, DecodeOpts{Filters: filters})
+}
+
+// DecodeOpts holds options used when decoding labels.
+type DecodeOpts struct {
+	// Filters skips the labels which do not match them.
+	Filters []string
+	// OnFill is called for each leaf value set into the element (see FillerOpts).
+	OnFill func(path, key string)
+}
+
+// DecodeWithOpts decodes the given map of labels into the given element using opts.
+func DecodeWithOpts(labels map[string]string, element interface{}, rootName string, opts DecodeOpts) error {
+	node, err := DecodeToNode(labels, rootName, opts.Filters...)
 	if err != nil {
 		return err
 	}
@@ -19,7 +32,7 @@ func Decode(labels map[string]string, element interface{}, rootName string, filt
 		return err
 	}
 
-	return Fill(element, node, FillerOpts{AllowSliceAsStruct: true})
+	return Fill(element, node, FillerOpts{AllowSliceAsStruct: true, OnFill: opts.OnFill})
 }
 
 // Encode converts an element to labels.
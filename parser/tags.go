@@ -23,4 +23,18 @@ const (
 
 	// TagLabelAllowEmpty is related to TagLabel.
 	TagLabelAllowEmpty = "allowEmpty"
+
+	// TagDefault is the default value of the field, parsed like any other value of the field.
+	// Slice values are comma separated, map entries are comma separated key=value pairs.
+	TagDefault = "default"
+
+	// TagSecret marks the value of the field as secret (i.e. secret:"true") so that it is masked when dumped.
+	TagSecret = "secret"
+
+	// TagFlag holds the custom name and the one-letter alias of the flag of the field (i.e. flag:"host,short=H").
+	// Both are optional and the flag named after the path of the field remains available.
+	TagFlag = "flag"
+
+	// TagValidate holds the comma separated validation rules of the field.
+	TagValidate = "validate"
 )
@@ -0,0 +1,40 @@
+package gonfig
+
+import (
+	"fmt"
+)
+
+// Origin describes where a configuration value comes from.
+type Origin struct {
+	Source Source
+	// Name is the file path, the environment variable name or the flag.
+	Name string
+	// Key is the key of the value in the file, if any.
+	Key string
+}
+
+// String returns a string representation of the origin.
+func (o Origin) String() string {
+	if o.Key != "" {
+		return fmt.Sprintf("%s %s (%s)", o.Source, o.Name, o.Key)
+	}
+	return fmt.Sprintf("%s %s", o.Source, o.Name)
+}
+
+// Provenance maps the dotted path of each configuration value (e.g. "server.ftp.host")
+// to the origin of the value.
+type Provenance map[string]Origin
+
+// ProvenanceLoader is a Loader that records the origin of the values it sets.
+type ProvenanceLoader interface {
+	Loader
+	// GetProvenance returns the origin of the values set by the last load.
+	GetProvenance() Provenance
+}
+
+// Merge copies the entries of other into p, overriding the existing ones.
+func (p Provenance) Merge(other Provenance) {
+	for path, origin := range other {
+		p[path] = origin
+	}
+}
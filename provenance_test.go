@@ -0,0 +1,50 @@
+package gonfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	example "github.com/crazy-max/gonfig/contrib/example/config"
+	"github.com/crazy-max/gonfig/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance(t *testing.T) {
+	t.Setenv(env.DefaultNamePrefix+"SERVER_FTP_USERNAME", "envuser")
+	t.Setenv(env.DefaultNamePrefix+"NOTIF_WEBHOOK_HEADERS_X", "envheader")
+
+	cfgfile, err := filepath.Abs("./fixtures/config.test.yml")
+	require.NoError(t, err)
+
+	cfg := &example.Config{}
+	res, err := Load(cfg,
+		NewFileLoader(FileLoaderConfig{Filename: cfgfile}),
+		NewEnvLoader(EnvLoaderConfig{Prefix: env.DefaultNamePrefix}),
+		NewFlagLoader(FlagLoaderConfig{Args: []string{"--server.ftp.disableEPSV=true", "--logLevel=debug"}}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, Origin{Source: SourceFile, Name: cfgfile, Key: "server.ftp.host"}, res.Provenance["server.ftp.host"])
+	assert.Equal(t, Origin{Source: SourceFile, Name: cfgfile, Key: "notif.webhook.headers.content-type"}, res.Provenance["notif.webhook.headers.content-type"])
+	assert.Equal(t, Origin{Source: SourceEnv, Name: "GONFIG_SERVER_FTP_USERNAME"}, res.Provenance["server.ftp.username"])
+	assert.Equal(t, Origin{Source: SourceEnv, Name: "GONFIG_NOTIF_WEBHOOK_HEADERS_X"}, res.Provenance["notif.webhook.headers.x"])
+	assert.Equal(t, Origin{Source: SourceFlag, Name: "--server.ftp.disableEPSV"}, res.Provenance["server.ftp.disableepsv"])
+	assert.Equal(t, Origin{Source: SourceFlag, Name: "--logLevel"}, res.Provenance["loglevel"])
+
+	assert.Equal(t, "env GONFIG_SERVER_FTP_USERNAME", res.Provenance["server.ftp.username"].String())
+	assert.Equal(t, "file "+cfgfile+" (server.ftp.host)", res.Provenance["server.ftp.host"].String())
+}
+
+func TestProvenance_flagAliases(t *testing.T) {
+	cfg := &struct {
+		Host string `flag:"host,short=H"`
+		Port int    `flag:",short=p"`
+	}{}
+
+	res, err := Load(cfg, NewFlagLoader(FlagLoaderConfig{Args: []string{"-H", "localhost", "--port=80"}}))
+	require.NoError(t, err)
+
+	assert.Equal(t, Origin{Source: SourceFlag, Name: "-H"}, res.Provenance["host"])
+	assert.Equal(t, Origin{Source: SourceFlag, Name: "--port"}, res.Provenance["port"])
+}
@@ -0,0 +1,139 @@
+package gonfig
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// Store is the structure holding a configuration that can be reloaded at runtime.
+// Each reload runs the loaders into a fresh configuration that is then swapped atomically,
+// so goroutines always get a consistent snapshot.
+type Store[T any] struct {
+	cfg     StoreConfig[T]
+	current atomic.Pointer[T]
+	result  atomic.Pointer[Result]
+
+	reloadMu    sync.Mutex
+	mu          sync.Mutex
+	nextID      int
+	subscribers []subscriber[T]
+}
+
+// subscriber is a callback registered with Store.Subscribe.
+type subscriber[T any] struct {
+	id int
+	fn func(oldCfg, newCfg *T)
+}
+
+// StoreConfig loads a configuration into a Store.
+type StoreConfig[T any] struct {
+	// Loaders to apply on each reload.
+	Loaders []Loader
+	// Precedence of the sources, see ChainConfig.
+	Precedence []Source
+	// Validate checks each reloaded configuration, see ChainConfig.
+	Validate bool
+	// New returns the fresh configuration the loaders are applied to.
+	// Default to a new T initialized with its default tags and SetDefaults if any.
+	New func() *T
+}
+
+// NewStore creates a new Store from the StoreConfig cfg.
+// Reload must be called to populate the store.
+func NewStore[T any](cfg StoreConfig[T]) *Store[T] {
+	return &Store[T]{
+		cfg: cfg,
+	}
+}
+
+// Load returns the current configuration or nil if the store has not been populated yet.
+// The returned configuration is shared and must not be modified.
+func (s *Store[T]) Load() *T {
+	return s.current.Load()
+}
+
+// GetResult returns the result of the last successful reload.
+func (s *Store[T]) GetResult() Result {
+	if res := s.result.Load(); res != nil {
+		return *res
+	}
+	return Result{}
+}
+
+// Reload runs the loaders into a fresh configuration and swaps it with the current one.
+// The current configuration is kept if any loader fails.
+func (s *Store[T]) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	newCfg, err := s.newConfig()
+	if err != nil {
+		return err
+	}
+
+	chain := NewChain(ChainConfig{
+		Loaders:    s.cfg.Loaders,
+		Precedence: s.cfg.Precedence,
+		Validate:   s.cfg.Validate,
+	})
+	// the default tags have already been applied by newConfig, or are left to New
+	if _, err := chain.load(newCfg); err != nil {
+		return err
+	}
+
+	res := chain.GetResult()
+	s.result.Store(&res)
+	oldCfg := s.current.Swap(newCfg)
+
+	s.mu.Lock()
+	subscribers := make([]subscriber[T], len(s.subscribers))
+	copy(subscribers, s.subscribers)
+	s.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.fn(oldCfg, newCfg)
+	}
+
+	return nil
+}
+
+// Subscribe registers a callback called with the previous and the new configuration
+// each time a new configuration is swapped in, after the ones registered before.
+// The returned function removes the subscription.
+func (s *Store[T]) Subscribe(fn func(oldCfg, newCfg *T)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscribers = append(s.subscribers, subscriber[T]{id: id, fn: fn})
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subscribers {
+			if sub.id == id {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *Store[T]) newConfig() (*T, error) {
+	if s.cfg.New != nil {
+		return s.cfg.New(), nil
+	}
+
+	cfg := new(T)
+	if err := parser.FillDefaults(cfg); err != nil {
+		return nil, err
+	}
+	if i, ok := interface{}(cfg).(initializer); ok {
+		i.SetDefaults()
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,103 @@
+package gonfig
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	cfgfile := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: first\n"), 0o644))
+
+	store := NewStore(StoreConfig[watchedConfig]{
+		Loaders: []Loader{
+			NewFlagLoader(FlagLoaderConfig{Args: []string{"--bar=1"}}),
+			NewFileLoader(FileLoaderConfig{Filename: cfgfile}),
+		},
+	})
+	assert.Nil(t, store.Load())
+
+	var changes [][2]*watchedConfig
+	unsubscribe := store.Subscribe(func(oldCfg, newCfg *watchedConfig) {
+		changes = append(changes, [2]*watchedConfig{oldCfg, newCfg})
+	})
+
+	require.NoError(t, store.Reload())
+	first := store.Load()
+	assert.Equal(t, &watchedConfig{Foo: "first", Bar: 1}, first)
+	assert.Equal(t, []Source{SourceFile, SourceFlag}, store.GetResult().Sources())
+
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: second\n"), 0o644))
+	require.NoError(t, store.Reload())
+	assert.Equal(t, &watchedConfig{Foo: "second", Bar: 1}, store.Load())
+	assert.Equal(t, &watchedConfig{Foo: "first", Bar: 1}, first)
+
+	require.Len(t, changes, 2)
+	assert.Nil(t, changes[0][0])
+	assert.Same(t, first, changes[1][0])
+	assert.Same(t, store.Load(), changes[1][1])
+
+	// the last good configuration is kept on failure
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: [invalid\n"), 0o644))
+	require.Error(t, store.Reload())
+	assert.Equal(t, &watchedConfig{Foo: "second", Bar: 1}, store.Load())
+
+	unsubscribe()
+	require.NoError(t, os.WriteFile(cfgfile, []byte("foo: third\n"), 0o644))
+	require.NoError(t, store.Reload())
+	assert.Len(t, changes, 2)
+}
+
+func TestStore_subscribeOrder(t *testing.T) {
+	store := NewStore(StoreConfig[watchedConfig]{})
+
+	var calls []int
+	unsubscribes := make([]func(), 10)
+	for i := range unsubscribes {
+		i := i
+		unsubscribes[i] = store.Subscribe(func(_, _ *watchedConfig) {
+			calls = append(calls, i)
+		})
+	}
+	unsubscribes[3]()
+
+	for n := 0; n < 5; n++ {
+		calls = nil
+		require.NoError(t, store.Reload())
+		assert.Equal(t, []int{0, 1, 2, 4, 5, 6, 7, 8, 9}, calls)
+	}
+}
+
+func TestStore_defaults(t *testing.T) {
+	store := NewStore(StoreConfig[watchedConfig]{})
+	require.NoError(t, store.Reload())
+	assert.Equal(t, &watchedConfig{Bar: 42}, store.Load())
+}
+
+func TestStore_concurrent(t *testing.T) {
+	store := NewStore(StoreConfig[watchedConfig]{
+		Loaders: []Loader{
+			NewFlagLoader(FlagLoaderConfig{Args: []string{"--foo=bar"}}),
+		},
+	})
+	require.NoError(t, store.Reload())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.Reload())
+		}()
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "bar", store.Load().Foo)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,16 @@
+package types
+
+// SecretMask replaces a secret value when displayed.
+const SecretMask = "********"
+
+// Secret is a string that holds a sensitive value.
+// It is masked when formatted or dumped.
+type Secret string
+
+// String returns the masked value, or an empty string if the secret is empty.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return SecretMask
+}
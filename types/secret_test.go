@@ -0,0 +1,14 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecret_String(t *testing.T) {
+	assert.Equal(t, SecretMask, Secret("password").String())
+	assert.Equal(t, SecretMask, fmt.Sprintf("%v", Secret("password")))
+	assert.Empty(t, Secret("").String())
+}
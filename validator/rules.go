@@ -0,0 +1,205 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/crazy-max/gonfig/types"
+)
+
+// rule checks a value against the param of the rule.
+// It returns a message describing the failure, or an error if the rule cannot be applied.
+type rule func(value reflect.Value, param string) (string, error)
+
+var rules = map[string]rule{
+	"min":      compareRule("min"),
+	"max":      compareRule("max"),
+	"len":      compareRule("len"),
+	"oneof":    oneOf,
+	"regexp":   matchRegexp,
+	"url":      isURL,
+	"hostname": isHostname,
+	"port":     isPort,
+	"file":     isFile,
+	"dir":      isDir,
+}
+
+var hostnameRegexp = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9]))*\.?$`)
+
+func compareRule(name string) rule {
+	return func(value reflect.Value, param string) (string, error) {
+		switch value.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				return "", err
+			}
+
+			size := value.Len()
+			if value.Kind() == reflect.String {
+				size = utf8.RuneCountInString(value.String())
+			}
+
+			return compare(name, "length ", float64(size), float64(n), param), nil
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := parseInt(value.Type(), param)
+			if err != nil {
+				return "", err
+			}
+
+			return compare(name, "", float64(value.Int()), float64(n), param), nil
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(param, 10, 64)
+			if err != nil {
+				return "", err
+			}
+
+			return compare(name, "", float64(value.Uint()), float64(n), param), nil
+
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return "", err
+			}
+
+			return compare(name, "", value.Float(), n, param), nil
+
+		default:
+			return "", fmt.Errorf("unsupported type: %s", value.Type())
+		}
+	}
+}
+
+func parseInt(rType reflect.Type, param string) (int64, error) {
+	switch rType {
+	case reflect.TypeOf(types.Duration(0)):
+		var d types.Duration
+		err := d.Set(param)
+		return int64(d), err
+	case reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(param)
+		return int64(d), err
+	default:
+		return strconv.ParseInt(param, 10, 64)
+	}
+}
+
+func compare(name, subject string, value, limit float64, param string) string {
+	switch {
+	case name == "min" && value < limit:
+		return fmt.Sprintf("%smust be at least %s", subject, param)
+	case name == "max" && value > limit:
+		return fmt.Sprintf("%smust be at most %s", subject, param)
+	case name == "len" && value != limit:
+		return fmt.Sprintf("%smust be %s", subject, param)
+	default:
+		return ""
+	}
+}
+
+func oneOf(value reflect.Value, param string) (string, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	allowed := strings.Fields(param)
+	for _, a := range allowed {
+		if s == a {
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf("must be one of [%s]", strings.Join(allowed, ", ")), nil
+}
+
+func matchRegexp(value reflect.Value, param string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return "", err
+	}
+
+	if !re.MatchString(value.String()) {
+		return fmt.Sprintf("must match %s", param), nil
+	}
+
+	return "", nil
+}
+
+func isURL(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+
+	u, err := url.Parse(value.String())
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "must be a valid URL", nil
+	}
+
+	return "", nil
+}
+
+func isHostname(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+
+	if len(value.String()) > 253 || !hostnameRegexp.MatchString(value.String()) {
+		return "must be a valid hostname", nil
+	}
+
+	return "", nil
+}
+
+func isPort(value reflect.Value, _ string) (string, error) {
+	s, err := stringValue(value)
+	if err != nil {
+		return "", err
+	}
+
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return "must be a valid port number", nil
+	}
+
+	return "", nil
+}
+
+func isFile(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+
+	fi, err := os.Stat(value.String())
+	if err != nil || fi.IsDir() {
+		return "must be an existing file", nil
+	}
+
+	return "", nil
+}
+
+func isDir(value reflect.Value, _ string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+
+	fi, err := os.Stat(value.String())
+	if err != nil || !fi.IsDir() {
+		return "must be an existing directory", nil
+	}
+
+	return "", nil
+}
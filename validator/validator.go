@@ -0,0 +1,254 @@
+// Package validator implements the validation of a typed Configuration using the validate struct tag.
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crazy-max/gonfig/flag"
+	"github.com/crazy-max/gonfig/parser"
+)
+
+// Opts holds options used when validating.
+type Opts struct {
+	// EnvPrefix is the prefix of the environment variables (e.g. "MYAPP_")
+	// used to name the variable of an invalid field. The variable is not named if empty.
+	EnvPrefix string
+}
+
+// FieldError is the validation error of a field.
+type FieldError struct {
+	// Path is the dotted path of the field (e.g. "server.ftp.port").
+	Path string
+	// Env is the environment variable setting the field, if any.
+	Env string
+	// Flag is the flag setting the field.
+	Flag string
+	// Rule is the rule that failed.
+	Rule string
+	// Message describes the failure.
+	Message string
+}
+
+// Error returns a string representation of the field error.
+func (e *FieldError) Error() string {
+	hints := []string{"flag: " + e.Flag}
+	if e.Env != "" {
+		hints = append([]string{"env: " + e.Env}, hints...)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Path, e.Message, strings.Join(hints, ", "))
+}
+
+// Errors is the list of the validation errors of an element.
+type Errors []*FieldError
+
+// Error returns a string representation of the validation errors.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid configuration: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks the fields of element against the rules of their validate tag.
+// The rules are comma separated: required, omitempty, min=N, max=N, len=N, oneof=a b c,
+// url, hostname, port, file, dir and regexp=PATTERN (which must be the last rule).
+// It returns Errors if any field is invalid, or another error if a rule is malformed.
+func Validate(element interface{}, opts Opts) error {
+	if element == nil {
+		return nil
+	}
+
+	// invalid flag tags are reported by the flag parser, the flags are then named after their path
+	aliases, _ := flag.Aliases(element)
+
+	v := &validator{Opts: opts, aliases: aliases}
+	if err := v.browse(reflect.ValueOf(element), ""); err != nil {
+		return err
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+
+	return nil
+}
+
+type validator struct {
+	Opts
+	aliases map[string]flag.Alias
+	errs    Errors
+}
+
+func (v *validator) browse(value reflect.Value, path string) error {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return v.browse(value.Elem(), path)
+
+	case reflect.Struct:
+		if value.Type() == reflect.TypeOf(time.Time{}) {
+			return nil
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !parser.IsExported(field) {
+				continue
+			}
+
+			if field.Anonymous {
+				if err := v.browse(value.Field(i), path); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fieldPath := joinPath(path, strings.ToLower(field.Name))
+
+			if rules := field.Tag.Get(parser.TagValidate); rules != "" && rules != "-" {
+				if err := v.check(value.Field(i), fieldPath, rules); err != nil {
+					return err
+				}
+			}
+
+			if err := v.browse(value.Field(i), fieldPath); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.browse(value.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		for _, key := range keys {
+			if err := v.browse(value.MapIndex(key), joinPath(path, key.String())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) check(value reflect.Value, path, tag string) error {
+	for _, r := range splitRules(tag) {
+		name, param, _ := strings.Cut(r, "=")
+
+		switch name {
+		case "omitempty":
+			if isEmpty(value) {
+				return nil
+			}
+			continue
+		case "required":
+			if isEmpty(value) {
+				v.addError(path, name, "is required")
+				return nil
+			}
+			continue
+		}
+
+		fn, ok := rules[name]
+		if !ok {
+			return fmt.Errorf("unknown validation rule %q on %s", name, path)
+		}
+
+		if value.Kind() == reflect.Pointer && value.IsNil() {
+			continue
+		}
+
+		msg, err := fn(reflect.Indirect(value), param)
+		if err != nil {
+			return fmt.Errorf("invalid validation rule %q on %s: %w", r, path, err)
+		}
+
+		if msg != "" {
+			v.addError(path, name, msg)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) addError(path, rule, msg string) {
+	fe := &FieldError{
+		Path:    path,
+		Flag:    "--" + path,
+		Rule:    rule,
+		Message: msg,
+	}
+
+	if alias, ok := v.aliases[path]; ok {
+		fe.Flag = alias.Name
+	}
+
+	if v.EnvPrefix != "" {
+		fe.Env = v.EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+	}
+
+	v.errs = append(v.errs, fe)
+}
+
+func splitRules(tag string) []string {
+	parts := strings.Split(tag, ",")
+
+	for i, part := range parts {
+		if strings.HasPrefix(part, "regexp=") {
+			// the pattern may contain commas
+			return append(parts[:i], strings.Join(parts[i:], ","))
+		}
+	}
+
+	return parts
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
+
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func stringValue(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	default:
+		return "", fmt.Errorf("unsupported type: %s", value.Type())
+	}
+}
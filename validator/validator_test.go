@@ -0,0 +1,243 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crazy-max/gonfig/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		desc     string
+		element  interface{}
+		expected []string
+	}{
+		{
+			desc: "nil",
+		},
+		{
+			desc: "valid",
+			element: &struct {
+				Host     string         `validate:"required,hostname"`
+				Port     int            `validate:"required,port"`
+				Level    string         `validate:"oneof=debug info warn"`
+				Name     string         `validate:"min=2,max=5"`
+				Code     string         `validate:"len=3"`
+				Endpoint string         `validate:"url"`
+				Output   string         `validate:"required,dir"`
+				Key      string         `validate:"omitempty,file"`
+				Pattern  string         `validate:"regexp=^[a-z]{1,3}$"`
+				Timeout  types.Duration `validate:"min=1s,max=60"`
+				Sources  []string       `validate:"min=1"`
+			}{
+				Host:     "test.rebex.net",
+				Port:     21,
+				Level:    "info",
+				Name:     "foo",
+				Code:     "abc",
+				Endpoint: "https://example.com/hook",
+				Output:   dir,
+				Pattern:  "ab",
+				Timeout:  types.Duration(5 * time.Second),
+				Sources:  []string{"/"},
+			},
+		},
+		{
+			desc: "required",
+			element: &struct {
+				Foo string `validate:"required"`
+				Bar *struct {
+					Baz int `validate:"required"`
+				} `validate:"required"`
+			}{},
+			expected: []string{
+				"foo: is required (env: MYAPP_FOO, flag: --foo)",
+				"bar: is required (env: MYAPP_BAR, flag: --bar)",
+			},
+		},
+		{
+			desc: "nested",
+			element: &struct {
+				Server *struct {
+					FTP *struct {
+						Port int `validate:"required,min=1"`
+					}
+				}
+			}{
+				Server: &struct {
+					FTP *struct {
+						Port int `validate:"required,min=1"`
+					}
+				}{
+					FTP: &struct {
+						Port int `validate:"required,min=1"`
+					}{Port: -1},
+				},
+			},
+			expected: []string{
+				"server.ftp.port: must be at least 1 (env: MYAPP_SERVER_FTP_PORT, flag: --server.ftp.port)",
+			},
+		},
+		{
+			desc: "invalid values",
+			element: &struct {
+				Host     string        `validate:"hostname"`
+				Port     string        `validate:"port"`
+				Level    string        `validate:"oneof=debug info"`
+				Name     string        `validate:"max=2"`
+				Code     []string      `validate:"len=1"`
+				Endpoint string        `validate:"url"`
+				Output   string        `validate:"dir"`
+				Key      string        `validate:"file"`
+				Pattern  string        `validate:"regexp=^a{1,2}$"`
+				Timeout  time.Duration `validate:"max=1m"`
+			}{
+				Host:     "-invalid-",
+				Port:     "70000",
+				Level:    "trace",
+				Name:     "foo",
+				Endpoint: "/foo",
+				Output:   "/does/not/exist",
+				Key:      dir,
+				Pattern:  "aaa",
+				Timeout:  2 * time.Minute,
+			},
+			expected: []string{
+				"host: must be a valid hostname (env: MYAPP_HOST, flag: --host)",
+				"port: must be a valid port number (env: MYAPP_PORT, flag: --port)",
+				"level: must be one of [debug, info] (env: MYAPP_LEVEL, flag: --level)",
+				"name: length must be at most 2 (env: MYAPP_NAME, flag: --name)",
+				"code: length must be 1 (env: MYAPP_CODE, flag: --code)",
+				"endpoint: must be a valid URL (env: MYAPP_ENDPOINT, flag: --endpoint)",
+				"output: must be an existing directory (env: MYAPP_OUTPUT, flag: --output)",
+				"key: must be an existing file (env: MYAPP_KEY, flag: --key)",
+				"pattern: must match ^a{1,2}$ (env: MYAPP_PATTERN, flag: --pattern)",
+				"timeout: must be at most 1m (env: MYAPP_TIMEOUT, flag: --timeout)",
+			},
+		},
+		{
+			desc: "slice and map of structs",
+			element: &struct {
+				Items []struct {
+					Name string `validate:"required"`
+				}
+				Hooks map[string]*struct {
+					URL string `validate:"url"`
+				}
+			}{
+				Items: []struct {
+					Name string `validate:"required"`
+				}{{Name: "foo"}, {}},
+				Hooks: map[string]*struct {
+					URL string `validate:"url"`
+				}{"MyHook": {URL: "foo"}},
+			},
+			expected: []string{
+				"items[1].name: is required (env: MYAPP_ITEMS[1]_NAME, flag: --items[1].name)",
+				"hooks.MyHook.url: must be a valid URL (env: MYAPP_HOOKS_MYHOOK_URL, flag: --hooks.MyHook.url)",
+			},
+		},
+		{
+			desc: "nil pointer with rules",
+			element: &struct {
+				Timeout *time.Duration `validate:"min=1s"`
+			}{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := Validate(test.element, Opts{EnvPrefix: "MYAPP_"})
+			if len(test.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var errs Errors
+			require.ErrorAs(t, err, &errs)
+
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			assert.Equal(t, test.expected, msgs)
+		})
+	}
+}
+
+func TestValidate_noEnvPrefix(t *testing.T) {
+	element := &struct {
+		Foo string `validate:"required"`
+	}{}
+
+	err := Validate(element, Opts{})
+	require.EqualError(t, err, "invalid configuration: foo: is required (flag: --foo)")
+}
+
+func TestValidate_errors(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		element interface{}
+	}{
+		{
+			desc: "unknown rule",
+			element: &struct {
+				Foo string `validate:"foo"`
+			}{},
+		},
+		{
+			desc: "invalid param",
+			element: &struct {
+				Foo int `validate:"min=a"`
+			}{},
+		},
+		{
+			desc: "unsupported type",
+			element: &struct {
+				Foo int `validate:"url"`
+			}{},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			err := Validate(test.element, Opts{})
+			require.Error(t, err)
+
+			var errs Errors
+			assert.NotErrorAs(t, err, &errs)
+		})
+	}
+}
+
+func TestValidate_flagAliases(t *testing.T) {
+	element := &struct {
+		Server struct {
+			Host string `flag:"host,short=H" validate:"required"`
+			Port int    `flag:",short=p" validate:"min=1"`
+		}
+	}{}
+
+	err := Validate(element, Opts{})
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "--host", errs[0].Flag)
+	assert.Equal(t, "--server.port", errs[1].Flag)
+}
+
+func TestValidate_structValue(t *testing.T) {
+	element := struct {
+		Host string `flag:"host,short=H" validate:"required"`
+		Port int    `validate:"port"`
+	}{Port: 21}
+
+	err := Validate(element, Opts{})
+	require.EqualError(t, err, "invalid configuration: host: is required (flag: --host)")
+}